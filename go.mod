@@ -3,6 +3,8 @@ module github.com/jbrusegaard/graph-struct-manager
 go 1.25
 
 require (
+	// Pinned: gremlin/driver reads unexported bytecode fields. Upgrade only
+	// together with readField; TestBytecodeLayout fails on another version.
 	github.com/apache/tinkerpop/gremlin-go/v3 v3.7.4
 	github.com/charmbracelet/lipgloss v1.1.0
 	github.com/charmbracelet/log v0.4.2
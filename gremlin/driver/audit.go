@@ -0,0 +1,369 @@
+package driver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// Auditable is implemented by models whose changes are recorded in the
+// audit trail:
+//
+//	func (Account) Audited() {}
+//
+// Create, Save, Updates, Delete, HardDelete and Restore record an
+// AuditRecord for every vertex of an Auditable model they change. A soft
+// Delete is recorded as an update setting deleted_at.
+type Auditable interface {
+	Audited()
+}
+
+// AuditOperation is the kind of change an AuditRecord describes.
+type AuditOperation string
+
+const (
+	AuditCreate AuditOperation = "create"
+	AuditUpdate AuditOperation = "update"
+	AuditDelete AuditOperation = "delete"
+)
+
+// AuditLabel is the label of the vertices audit records are written to
+// without a Config.AuditSink, and AuditEdgeLabel the label of the edge from
+// each of them to the vertex it describes.
+const (
+	AuditLabel     = "audit"
+	AuditEdgeLabel = "audits"
+)
+
+// AuditChange is the value of a property before and after a change. Old is
+// nil for created properties and New for deleted ones.
+type AuditChange struct {
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// AuditRecord describes a change to a vertex of an Auditable model.
+type AuditRecord struct {
+	Operation AuditOperation
+	// Label and VertexID identify the changed vertex.
+	Label    string
+	VertexID any
+	// Actor is the actor of the driver's context, see WithActor.
+	Actor string
+	// Changes holds the changed properties by name. The created and last
+	// modified timestamps are left out; Time records when the change was
+	// made.
+	Changes map[string]AuditChange
+	Time    time.Time
+}
+
+// AuditSink receives the audit records of a driver instead of the graph.
+type AuditSink interface {
+	Record(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to an AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+func (f AuditSinkFunc) Record(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor recorded in audit
+// records, such as the authenticated user of a request.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set on ctx by WithActor, or "".
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// WithContext returns a view of the driver bound to ctx, whose actor is
+// recorded in the audit records of the changes made through it:
+//
+//	db := db.WithContext(driver.WithActor(r.Context(), user.Email))
+//	err := driver.Save(db, &account)
+//
+// Cancelling the context stops retries between attempts but does not cancel
+// a request in flight; see Config.QueryTimeout. The view
+// shares the connections of driver, so closing it is a no-op; close driver
+// instead.
+func (driver *GremlinDriver) WithContext(ctx context.Context) *GremlinDriver {
+	view := driver.view()
+	view.ctx = ctx
+	return view
+}
+
+// Context returns the context the driver is bound to by WithContext, or
+// context.Background().
+func (driver *GremlinDriver) Context() context.Context {
+	if driver.ctx == nil {
+		return context.Background()
+	}
+	return driver.ctx
+}
+
+var auditableType = reflect.TypeFor[Auditable]()
+
+// typeIsAuditable reports whether rt is a struct implementing Auditable with
+// a value or pointer receiver.
+func typeIsAuditable(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && reflect.PointerTo(rt).Implements(auditableType)
+}
+
+// auditedVertex is a vertex as read before an audited change.
+type auditedVertex struct {
+	id         any
+	label      string
+	properties map[string]any
+}
+
+// auditSnapshot reads the given properties, or all of them when keys is
+// empty, of the vertices reached by query. query is not modified.
+func (driver *GremlinDriver) auditSnapshot(
+	query *gremlingo.GraphTraversal,
+	keys []string,
+) ([]auditedVertex, error) {
+	valueMapArgs := make([]any, len(keys))
+	for i, key := range keys {
+		valueMapArgs[i] = key
+	}
+	results, err := driver.toList(
+		query.Clone().
+			Project("id", "label", "properties").
+			By(anonymousTraversal.Id()).
+			By(anonymousTraversal.Label()).
+			By(anonymousTraversal.ValueMap(valueMapArgs...)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("audit: reading previous values: %w", err)
+	}
+	vertices := make([]auditedVertex, 0, len(results))
+	for _, result := range results {
+		data, ok := result.GetInterface().(map[any]any)
+		if !ok {
+			return nil, fmt.Errorf("audit: unexpected previous values %v", result.GetInterface())
+		}
+		vertex := auditedVertex{id: data["id"], properties: map[string]any{}}
+		vertex.label, _ = data["label"].(string)
+		properties, _ := data["properties"].(map[any]any)
+		for key, value := range properties {
+			if name, isString := key.(string); isString {
+				vertex.properties[name] = singleValue(value)
+			}
+		}
+		vertices = append(vertices, vertex)
+	}
+	return vertices, nil
+}
+
+// auditChanges returns the properties whose value differs between old and
+// updated, skipping the timestamps.
+func (driver *GremlinDriver) auditChanges(old, updated map[string]any) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+	add := func(key string) {
+		if key == driver.timestamps.createdAt || key == driver.timestamps.lastModified {
+			return
+		}
+		if _, seen := changes[key]; seen || sameAuditValue(old[key], updated[key]) {
+			return
+		}
+		changes[key] = AuditChange{Old: old[key], New: updated[key]}
+	}
+	for key := range old {
+		add(key)
+	}
+	for key := range updated {
+		add(key)
+	}
+	return changes
+}
+
+// sameAuditValue reports whether a and b hold the same property value,
+// regardless of the numeric and slice types the server returns it as.
+func sameAuditValue(a, b any) bool {
+	a, b = singleValue(a), singleValue(b)
+	if ta, ok := a.(time.Time); ok {
+		tb, isTime := b.(time.Time)
+		return isTime && ta.Equal(tb)
+	}
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+// singleValue returns the element of single element slices, nil for empty
+// ones and value itself otherwise, matching the lists of valueMap to the
+// values written.
+func singleValue(value any) any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return value
+	}
+	switch rv.Len() {
+	case 0:
+		return nil
+	case 1:
+		return rv.Index(0).Interface()
+	}
+	values := make([]any, rv.Len())
+	for i := range rv.Len() {
+		values[i] = rv.Index(i).Interface()
+	}
+	return values
+}
+
+// auditedWrite runs write, which changes the graph and returns the audit
+// records of the change, and records them when auditable is set. The change
+// and its audit vertices run in one transaction: the driver's own, or a new
+// one. When the graph cannot run transactions the audit vertices are
+// written after the change, best effort: failing to write them is logged and
+// does not fail the change. Records reach an AuditSink, best effort too,
+// once the change is written.
+func (driver *GremlinDriver) auditedWrite(
+	auditable bool,
+	write func(db *GremlinDriver) ([]AuditRecord, error),
+) error {
+	if !auditable {
+		_, err := write(driver)
+		return err
+	}
+	var records []AuditRecord
+	writeAudited := func(db *GremlinDriver) error {
+		var err error
+		if records, err = write(db); err != nil {
+			return err
+		}
+		records = db.completeAudit(records)
+		return db.writeAuditVertices(records)
+	}
+	var err error
+	if driver.tx != nil {
+		err = writeAudited(driver)
+	} else if err = driver.Transaction(writeAudited); errors.Is(err, ErrTransactionUnsupported) {
+		if records, err = write(driver); err == nil {
+			records = driver.completeAudit(records)
+			if auditErr := driver.writeAuditVertices(records); auditErr != nil {
+				driver.logger.Errorf("Change written without its audit record: %v", auditErr)
+			}
+		}
+	}
+	if err != nil {
+		return err
+	}
+	driver.sendAudit(records)
+	return nil
+}
+
+// completeAudit returns records with the actor and time set, leaving out
+// records without changes.
+func (driver *GremlinDriver) completeAudit(records []AuditRecord) []AuditRecord {
+	actor := ActorFrom(driver.Context())
+	now := driver.timestamps.now()
+	completed := make([]AuditRecord, 0, len(records))
+	for _, record := range records {
+		if len(record.Changes) == 0 {
+			continue
+		}
+		record.Actor = actor
+		record.Time = now
+		completed = append(completed, record)
+	}
+	return completed
+}
+
+// sendAudit hands records to the configured AuditSink, if any, logging the
+// records it fails to take.
+func (driver *GremlinDriver) sendAudit(records []AuditRecord) {
+	if driver.auditSink == nil {
+		return
+	}
+	for _, record := range records {
+		if err := driver.auditSink.Record(driver.Context(), record); err != nil {
+			driver.logger.Errorf(
+				"Audit sink failed to record %s of %v: %v", record.Operation, record.VertexID, err,
+			)
+		}
+	}
+}
+
+// writeAuditVertices writes records as audit vertices, each linked to the
+// vertex it describes unless that vertex was deleted. Nothing is written to
+// the graph when an AuditSink is configured.
+func (driver *GremlinDriver) writeAuditVertices(records []AuditRecord) error {
+	if driver.auditSink != nil {
+		return nil
+	}
+	for _, record := range records {
+		changes, err := json.Marshal(record.Changes)
+		if err != nil {
+			return fmt.Errorf("audit: encoding changes of %v: %w", record.VertexID, err)
+		}
+		query := driver.g.AddV(AuditLabel).
+			Property(gremlingo.Cardinality.Single, "operation", string(record.Operation)).
+			Property(gremlingo.Cardinality.Single, "actor", record.Actor).
+			Property(gremlingo.Cardinality.Single, "vertex_label", record.Label).
+			Property(gremlingo.Cardinality.Single, "vertex_id", record.VertexID).
+			Property(gremlingo.Cardinality.Single, "changes", string(changes)).
+			Property(gremlingo.Cardinality.Single, driver.timestamps.createdAt, driver.timestamps.value(record.Time))
+		if record.Operation != AuditDelete {
+			query = query.AddE(AuditEdgeLabel).To(anonymousTraversal.V(record.VertexID))
+		}
+		if err = driver.iterate(query); err != nil {
+			return fmt.Errorf("audit: recording %s of %v: %w", record.Operation, record.VertexID, err)
+		}
+	}
+	return nil
+}
+
+// audited runs write on the vertices the query matches and, for Auditable
+// models, records an operation setting the given properties to updated on
+// each of them, or removing them when updated is nil. keys empty snapshots
+// every property. write receives the driver to run on, which is a
+// transaction when the change is audited, and the query built on it.
+func (q *Query[T]) audited(
+	operation AuditOperation,
+	keys []string,
+	updated map[string]any,
+	write func(db *GremlinDriver, query *gremlingo.GraphTraversal) error,
+) error {
+	auditable := schemaFor(reflect.TypeFor[T]()).auditable
+	return q.db.auditedWrite(
+		auditable, func(db *GremlinDriver) ([]AuditRecord, error) {
+			bound := *q
+			bound.db = db
+			query := bound.BuildQuery()
+			var before []auditedVertex
+			if auditable {
+				var err error
+				if before, err = db.auditSnapshot(query, keys); err != nil {
+					return nil, err
+				}
+			}
+			if err := write(db, query); err != nil {
+				return nil, err
+			}
+			records := make([]AuditRecord, len(before))
+			for i, vertex := range before {
+				records[i] = AuditRecord{
+					Operation: operation,
+					Label:     vertex.label,
+					VertexID:  vertex.id,
+					Changes:   db.auditChanges(vertex.properties, updated),
+				}
+			}
+			return records, nil
+		},
+	)
+}
@@ -0,0 +1,223 @@
+package driver_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testAuditedTopic struct {
+	gsmtypes.Vertex
+	Title string   `json:"title" gremlin:"title"`
+	Tags  []string `json:"tags"  gremlin:"tags"`
+}
+
+func (testAuditedTopic) Audited() {}
+
+type testAuditedPost struct {
+	gsmtypes.Vertex
+	gsmtypes.SoftDelete
+	Title string `json:"title" gremlin:"title"`
+}
+
+func (testAuditedPost) Audited() {}
+
+// testSnapshot is the result of the audit trail reading vertex 1 before a
+// change.
+func testSnapshot(properties map[any]any) map[any]any {
+	return map[any]any{"id": "1", "label": "test_audited_topic", "properties": properties}
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		responses [][]any
+		run       func(db *driver.GremlinDriver) error
+		want      []driver.AuditRecord
+	}{
+		{
+			name:      "Create",
+			responses: [][]any{{"1"}},
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Create(db, &testAuditedTopic{Title: "graphs", Tags: []string{"a", "b"}})
+			},
+			want: []driver.AuditRecord{
+				{
+					Operation: driver.AuditCreate,
+					Label:     "test_audited_topic",
+					VertexID:  "1",
+					Changes: map[string]driver.AuditChange{
+						"title": {New: "graphs"},
+						"tags":  {New: []string{"a", "b"}},
+					},
+				},
+			},
+		},
+		{
+			name: "Save",
+			responses: [][]any{
+				{testSnapshot(map[any]any{"title": []any{"graphs"}, "tags": []any{"a"}})},
+				{"1"},
+			},
+			run: func(db *driver.GremlinDriver) error {
+				topic := testAuditedTopic{Title: "trees", Tags: []string{"a"}}
+				topic.ID = "1"
+				return driver.Save(db, &topic)
+			},
+			want: []driver.AuditRecord{
+				{
+					Operation: driver.AuditUpdate,
+					Label:     "test_audited_topic",
+					VertexID:  "1",
+					Changes:   map[string]driver.AuditChange{"title": {Old: "graphs", New: "trees"}},
+				},
+			},
+		},
+		{
+			name: "Updates",
+			responses: [][]any{
+				{
+					testSnapshot(map[any]any{"title": []any{"graphs"}}),
+					map[any]any{"id": "2", "label": "test_audited_topic", "properties": map[any]any{"title": []any{"trees"}}},
+				},
+				{},
+			},
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testAuditedTopic](db).
+					Where("title", comparator.IN, []string{"graphs", "trees"}).
+					Updates(map[string]any{"title": "trees"})
+			},
+			want: []driver.AuditRecord{
+				{
+					Operation: driver.AuditUpdate,
+					Label:     "test_audited_topic",
+					VertexID:  "1",
+					Changes:   map[string]driver.AuditChange{"title": {Old: "graphs", New: "trees"}},
+				},
+			},
+		},
+		{
+			name: "Delete",
+			responses: [][]any{
+				{testSnapshot(map[any]any{"title": []any{"graphs"}, "created_at": []any{testClockTime}})},
+				{},
+			},
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testAuditedTopic](db).Where("title", comparator.EQ, "graphs").Delete()
+			},
+			want: []driver.AuditRecord{
+				{
+					Operation: driver.AuditDelete,
+					Label:     "test_audited_topic",
+					VertexID:  "1",
+					Changes:   map[string]driver.AuditChange{"title": {Old: "graphs"}},
+				},
+			},
+		},
+		{
+			name: "SoftDelete",
+			responses: [][]any{
+				{map[any]any{"id": "1", "label": "test_audited_post", "properties": map[any]any{}}},
+				{},
+			},
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testAuditedPost](db).Where("title", comparator.EQ, "graphs").Delete()
+			},
+			want: []driver.AuditRecord{
+				{
+					Operation: driver.AuditUpdate,
+					Label:     "test_audited_post",
+					VertexID:  "1",
+					Changes:   map[string]driver.AuditChange{gsmtypes.DeletedAt: {New: testClockTime}},
+				},
+			},
+		},
+		{
+			name:      "NotAuditable",
+			responses: [][]any{{"1"}},
+			run:       func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: tt.responses}
+				var records []driver.AuditRecord
+				sink := driver.AuditSinkFunc(
+					func(ctx context.Context, record driver.AuditRecord) error {
+						records = append(records, record)
+						return nil
+					},
+				)
+				db := driver.NewScriptDriverForTest(driver.Config{Clock: testClock, AuditSink: sink}, server.submit)
+				if err := tt.run(db.WithContext(driver.WithActor(context.Background(), "alice"))); err != nil {
+					t.Fatal(err)
+				}
+				for i := range tt.want {
+					tt.want[i].Actor = "alice"
+					tt.want[i].Time = testClockTime
+				}
+				if !reflect.DeepEqual(records, tt.want) {
+					t.Errorf("Expected records %+v, got %+v", tt.want, records)
+				}
+				if len(server.requests) != len(tt.responses) {
+					t.Errorf("Expected %d requests, got %d", len(tt.responses), len(server.requests))
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"AuditVertex", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{"1"}, {}}}
+			db := driver.NewScriptDriverForTest(driver.Config{Clock: testClock}, server.submit)
+			db = db.WithContext(driver.WithActor(context.Background(), "alice"))
+			if err := driver.Create(db, &testAuditedTopic{Title: "graphs"}); err != nil {
+				t.Fatal(err)
+			}
+			if len(server.requests) != 2 {
+				t.Fatalf("Expected the create and the audit vertex requests, got %d", len(server.requests))
+			}
+			script := server.requests[1].resolvedScript()
+			for _, want := range []string{
+				`g.addV("audit").property(single, "operation", "create").property(single, "actor", "alice")`,
+				`.property(single, "vertex_id", "1")`,
+				`.property(single, "changes", "{\"title\":{\"new\":\"graphs\"}}")`,
+				`.property(single, "created_at", 2024-05-01 12:30:00 +0000 UTC)`,
+				`.addE("audits").to(__.V("1"))`,
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected script to contain %s, got %s", want, script)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"AuditVertexFailureKeepsChange", func(t *testing.T) {
+			t.Parallel()
+			// The audit vertex request gets no response, so it fails after
+			// the change was written.
+			server := &recordedServer{responses: [][]any{{"1"}}}
+			db := driver.NewScriptDriverForTest(driver.Config{Clock: testClock}, server.submit)
+			topic := testAuditedTopic{Title: "graphs"}
+			if err := driver.Create(db, &topic); err != nil {
+				t.Fatalf("Expected the written change to succeed, got %v", err)
+			}
+			if topic.ID != "1" {
+				t.Errorf("Expected the created vertex ID 1, got %v", topic.ID)
+			}
+			if len(server.requests) != 2 {
+				t.Errorf("Expected the create and the audit vertex requests, got %d", len(server.requests))
+			}
+		},
+	)
+}
@@ -0,0 +1,472 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+const (
+	defaultManagedGraph      = "graph"
+	defaultMixedIndexBackend = "search"
+)
+
+// AutoMigrateOptions configures AutoMigrateWithOptions.
+type AutoMigrateOptions struct {
+	// DryRun prints the management script to Output instead of running it.
+	// The live schema is still read to work out what is missing.
+	DryRun bool
+	// Output receives the dry-run script (default os.Stdout).
+	Output io.Writer
+	// Graph is the name the server binds the JanusGraph instance to
+	// (default "graph").
+	Graph string
+	// MixedIndexBackend is the indexing backend mixed indexes are built on,
+	// as configured in the graph's index.<name>.backend (default "search").
+	MixedIndexBackend string
+}
+
+func (o AutoMigrateOptions) withDefaults() AutoMigrateOptions {
+	if o.Output == nil {
+		o.Output = os.Stdout
+	}
+	if o.Graph == "" {
+		o.Graph = defaultManagedGraph
+	}
+	if o.MixedIndexBackend == "" {
+		o.MixedIndexBackend = defaultMixedIndexBackend
+	}
+	return o
+}
+
+// propertyKeyDef is a property key derived from a model field.
+type propertyKeyDef struct {
+	name        string
+	dataType    string
+	cardinality string
+	// owner is the model that declared the key first, for conflict errors
+	owner reflect.Type
+}
+
+// indexDef is a vertex index over property keys, limited to label.
+type indexDef struct {
+	name   string
+	label  string
+	keys   []string
+	unique bool
+	mixed  bool
+	// reindex is set for indexes over property keys that already exist,
+	// whose data the index must be built over before it is enabled
+	reindex bool
+}
+
+// schemaPlan is the JanusGraph schema a set of models needs.
+type schemaPlan struct {
+	propertyKeys []propertyKeyDef
+	vertexLabels []string
+	edgeLabels   []string
+	indexes      []indexDef
+}
+
+// liveSchema holds the names of the schema elements defined in the graph.
+type liveSchema struct {
+	propertyKeys map[string]bool
+	vertexLabels map[string]bool
+	edgeLabels   map[string]bool
+	indexes      map[string]bool
+}
+
+// AutoMigrate creates the JanusGraph schema for models: a property key for
+// every gremlin tagged field, a vertex label per model, an edge label per
+// gremlinEdge field, an index per field tagged index or mixed and a unique
+// composite index per unique constraint.
+// Definitions already in the graph are left alone, so it is safe to run on
+// every start up. Models are struct values or pointers:
+//
+//	err := driver.AutoMigrate(db, &User{}, &Post{})
+func AutoMigrate(db *GremlinDriver, models ...any) error {
+	return AutoMigrateWithOptions(db, AutoMigrateOptions{}, models...)
+}
+
+// AutoMigrateWithOptions is AutoMigrate with options, e.g. a dry run.
+func AutoMigrateWithOptions(db *GremlinDriver, opts AutoMigrateOptions, models ...any) error {
+	if !db.dialect.Supports(FeatureSchemaManagement) {
+		return fmt.Errorf("AutoMigrate: schema management is not supported by %s", db.dialect.Name())
+	}
+	opts = opts.withDefaults()
+	plan, err := planSchema(db.dialect, db.timestamps, models)
+	if err != nil {
+		return fmt.Errorf("AutoMigrate: %w", err)
+	}
+	live, err := db.readLiveSchema(opts.Graph)
+	if err != nil {
+		return fmt.Errorf("AutoMigrate: failed to read schema: %w", err)
+	}
+	missing := plan.missing(live)
+	if missing.size() == 0 {
+		db.logger.Infof("Schema is up to date")
+		return nil
+	}
+	script := missing.script(opts)
+	if opts.DryRun {
+		_, err = fmt.Fprintln(opts.Output, script)
+		return err
+	}
+	if _, err = db.runScript(script); err != nil {
+		return fmt.Errorf("AutoMigrate: %w", err)
+	}
+	db.logger.Infof("Applied %d schema definitions", missing.size())
+	return nil
+}
+
+// planSchema derives the schema models need. The timestamp keys follow the
+// names and format of stamps.
+func planSchema(dialect Dialect, stamps timestamps, models []any) (*schemaPlan, error) {
+	plan := &schemaPlan{}
+	keys := make(map[string]*propertyKeyDef)
+	seen := make(map[string]bool)
+	for _, model := range models {
+		rt := reflect.TypeOf(model)
+		for rt != nil && rt.Kind() == reflect.Pointer {
+			rt = rt.Elem()
+		}
+		if rt == nil || rt.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("model %T is not a struct", model)
+		}
+		schema := schemaFor(rt)
+		label := schema.zeroLabel
+		if !seen["v:"+label] {
+			seen["v:"+label] = true
+			plan.vertexLabels = append(plan.vertexLabels, label)
+		}
+
+		for _, field := range schema.mapFields {
+			if field.tagName == "id" {
+				continue
+			}
+			key := propertyKeyDef{name: field.tagName, owner: rt}
+			fieldType := rt.FieldByIndex(field.index).Type
+			if name, ok := stamps.timestampKey(field.tagName, fieldType, !schema.noTimestamps); ok {
+				if name == "" {
+					continue
+				}
+				key.name, key.dataType, key.cardinality = name, stamps.dataType(), "SINGLE"
+			} else {
+				var err error
+				key.dataType, key.cardinality, err = propertyKeyType(fieldType, dialect)
+				if err != nil {
+					return nil, fmt.Errorf("%s.%s: %w", rt, field.goName, err)
+				}
+			}
+			if existing, ok := keys[key.name]; ok {
+				if existing.dataType != key.dataType || existing.cardinality != key.cardinality {
+					return nil, fmt.Errorf(
+						"property %q is %s %s in %s but %s %s in %s",
+						key.name, existing.cardinality, existing.dataType, existing.owner,
+						key.cardinality, key.dataType, rt,
+					)
+				}
+			} else {
+				keys[key.name] = &key
+				plan.propertyKeys = append(plan.propertyKeys, key)
+			}
+			for _, index := range fieldIndexes(label, field) {
+				if !seen["i:"+index.name] {
+					seen["i:"+index.name] = true
+					plan.indexes = append(plan.indexes, index)
+				}
+			}
+		}
+		for _, group := range schema.uniqueGroups {
+			index := indexDef{name: label + "_" + group.name + "_unique", label: label, keys: group.fields, unique: true}
+			if !seen["i:"+index.name] {
+				seen["i:"+index.name] = true
+				plan.indexes = append(plan.indexes, index)
+			}
+		}
+
+		for _, field := range schema.unloadFields {
+			if !field.isEdge {
+				continue
+			}
+			tagOpts, err := parseGremlinEdgeTag(rt.FieldByIndex(field.index).Tag.Get(gsmtypes.GremlinEdgeTag))
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", rt, field.goName, err)
+			}
+			if !seen["e:"+tagOpts.label] {
+				seen["e:"+tagOpts.label] = true
+				plan.edgeLabels = append(plan.edgeLabels, tagOpts.label)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// timestampKey returns the property name a time.Time field tagged tagName
+// is stored under when it holds a timestamp the driver maintains, and ok
+// false for other fields. The name is empty for the created and last
+// modified timestamps of models that disable them, which are not stored.
+func (t timestamps) timestampKey(tagName string, fieldType reflect.Type, enabled bool) (string, bool) {
+	if fieldType != timeType {
+		return "", false
+	}
+	name := ""
+	switch tagName {
+	case gsmtypes.DeletedAt:
+		return tagName, true
+	case gsmtypes.CreatedAt:
+		name = t.createdAt
+	case gsmtypes.LastModified:
+		name = t.lastModified
+	default:
+		return "", false
+	}
+	if !enabled {
+		return "", true
+	}
+	return name, true
+}
+
+// dataType returns the JanusGraph data type class of the configured
+// timestamp format.
+func (t timestamps) dataType() string {
+	switch t.format {
+	case TimestampEpochMillis:
+		return "Long"
+	case TimestampRFC3339:
+		return "String"
+	}
+	return "Date"
+}
+
+// fieldIndexes returns the non-unique indexes field's tag options ask for.
+// Unique indexes come from the type's unique groups.
+func fieldIndexes(label string, field fieldSchema) []indexDef {
+	var indexes []indexDef
+	if field.indexed && field.uniqueGroup == "" {
+		indexes = append(indexes, indexDef{
+			name: label + "_" + field.tagName + "_idx", label: label, keys: []string{field.tagName},
+		})
+	}
+	if field.mixed {
+		indexes = append(indexes, indexDef{
+			name: label + "_" + field.tagName + "_mixed", label: label, keys: []string{field.tagName}, mixed: true,
+		})
+	}
+	return indexes
+}
+
+// propertyKeyType returns the JanusGraph data type class and cardinality for
+// a field of type rt. Slices are stored one property per element with the
+// dialect's slice cardinality.
+func propertyKeyType(rt reflect.Type, dialect Dialect) (string, string, error) {
+	for rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	cardinality := "SINGLE"
+	if rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array {
+		rt = rt.Elem()
+		for rt.Kind() == reflect.Pointer {
+			rt = rt.Elem()
+		}
+		cardinality = "LIST"
+		if dialect.SliceCardinality() == gremlingo.Cardinality.Set {
+			cardinality = "SET"
+		}
+	}
+	switch rt {
+	case reflect.TypeFor[time.Time]():
+		return "Date", cardinality, nil
+	case reflect.TypeFor[time.Duration]():
+		return "java.time.Duration", cardinality, nil
+	}
+	switch rt.Kind() { //nolint:exhaustive // remaining kinds are unsupported
+	case reflect.String:
+		return "String", cardinality, nil
+	case reflect.Bool:
+		return "Boolean", cardinality, nil
+	case reflect.Int8:
+		return "Byte", cardinality, nil
+	case reflect.Int16, reflect.Uint8:
+		return "Short", cardinality, nil
+	case reflect.Int32, reflect.Uint16:
+		return "Integer", cardinality, nil
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return "Long", cardinality, nil
+	case reflect.Float32:
+		return "Float", cardinality, nil
+	case reflect.Float64:
+		return "Double", cardinality, nil
+	case reflect.Interface:
+		return "Object", cardinality, nil
+	}
+	return "", "", fmt.Errorf("type %s has no JanusGraph data type", rt)
+}
+
+// missing returns the part of the plan not yet defined in live.
+func (p *schemaPlan) missing(live *liveSchema) *schemaPlan {
+	missing := &schemaPlan{}
+	for _, key := range p.propertyKeys {
+		if !live.propertyKeys[key.name] {
+			missing.propertyKeys = append(missing.propertyKeys, key)
+		}
+	}
+	for _, label := range p.vertexLabels {
+		if !live.vertexLabels[label] {
+			missing.vertexLabels = append(missing.vertexLabels, label)
+		}
+	}
+	for _, label := range p.edgeLabels {
+		if !live.edgeLabels[label] {
+			missing.edgeLabels = append(missing.edgeLabels, label)
+		}
+	}
+	for _, index := range p.indexes {
+		if !live.indexes[index.name] {
+			index.reindex = slices.ContainsFunc(index.keys, func(key string) bool { return live.propertyKeys[key] })
+			missing.indexes = append(missing.indexes, index)
+		}
+	}
+	return missing
+}
+
+// size returns the number of definitions in the plan.
+func (p *schemaPlan) size() int {
+	return len(p.propertyKeys) + len(p.vertexLabels) + len(p.edgeLabels) + len(p.indexes)
+}
+
+// script renders the plan as a JanusGraph management script that defines
+// everything in one management transaction. Unique indexes lock their keys
+// so JanusGraph enforces them under concurrent writes. Indexes over
+// existing property keys are created INSTALLED and would never be used, so
+// the script then waits for them to be registered, reindexes the existing
+// data and waits for them to be enabled.
+func (p *schemaPlan) script(opts AutoMigrateOptions) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "mgmt = %s.openManagement()\n", opts.Graph)
+	sb.WriteString("try {\n")
+	for _, key := range p.propertyKeys {
+		fmt.Fprintf(
+			&sb, "  mgmt.makePropertyKey(%s).dataType(%s.class).cardinality(org.janusgraph.core.Cardinality.%s).make()\n",
+			groovyString(key.name), key.dataType, key.cardinality,
+		)
+	}
+	for _, label := range p.vertexLabels {
+		fmt.Fprintf(&sb, "  mgmt.makeVertexLabel(%s).make()\n", groovyString(label))
+	}
+	for _, label := range p.edgeLabels {
+		fmt.Fprintf(&sb, "  mgmt.makeEdgeLabel(%s).make()\n", groovyString(label))
+	}
+	var reindexed []string
+	for _, index := range p.indexes {
+		fmt.Fprintf(&sb, "  index = mgmt.buildIndex(%s, Vertex.class)", groovyString(index.name))
+		for _, key := range index.keys {
+			fmt.Fprintf(&sb, ".addKey(mgmt.getPropertyKey(%s))", groovyString(key))
+		}
+		fmt.Fprintf(&sb, ".indexOnly(mgmt.getVertexLabel(%s))", groovyString(index.label))
+		switch {
+		case index.mixed:
+			fmt.Fprintf(&sb, ".buildMixedIndex(%s)\n", groovyString(opts.MixedIndexBackend))
+		case index.unique:
+			sb.WriteString(".unique().buildCompositeIndex()\n")
+		default:
+			sb.WriteString(".buildCompositeIndex()\n")
+		}
+		if index.unique {
+			sb.WriteString("  mgmt.setConsistency(index, org.janusgraph.core.schema.ConsistencyModifier.LOCK)\n")
+		}
+		if index.reindex {
+			reindexed = append(reindexed, groovyString(index.name))
+		}
+	}
+	sb.WriteString("  mgmt.commit()\n")
+	sb.WriteString("} catch (e) {\n  mgmt.rollback()\n  throw e\n}")
+	if len(reindexed) == 0 {
+		return sb.String()
+	}
+	for _, name := range reindexed {
+		fmt.Fprintf(&sb, "\n%s.awaitGraphIndexStatus(%s, %s).call()", managementSystem, opts.Graph, name)
+	}
+	fmt.Fprintf(&sb, "\nmgmt = %s.openManagement()\ntry {\n", opts.Graph)
+	for _, name := range reindexed {
+		fmt.Fprintf(
+			&sb, "  mgmt.updateIndex(mgmt.getGraphIndex(%s), org.janusgraph.core.schema.SchemaAction.REINDEX).get()\n",
+			name,
+		)
+	}
+	sb.WriteString("  mgmt.commit()\n")
+	sb.WriteString("} catch (e) {\n  mgmt.rollback()\n  throw e\n}")
+	for _, name := range reindexed {
+		fmt.Fprintf(
+			&sb, "\n%s.awaitGraphIndexStatus(%s, %s).status(org.janusgraph.core.schema.SchemaStatus.ENABLED).call()",
+			managementSystem, opts.Graph, name,
+		)
+	}
+	return sb.String()
+}
+
+// managementSystem is the JanusGraph class whose awaitGraphIndexStatus waits
+// for an index to reach a status.
+const managementSystem = "org.janusgraph.graphdb.database.management.ManagementSystem"
+
+// liveSchemaScript lists the names of the property keys, labels and vertex
+// indexes defined in the graph.
+const liveSchemaScript = `mgmt = %s.openManagement()
+try {
+  [[propertyKeys: mgmt.getRelationTypes(org.janusgraph.core.PropertyKey.class).collect { it.name() },
+    vertexLabels: mgmt.getVertexLabels().collect { it.name() },
+    edgeLabels: mgmt.getRelationTypes(org.janusgraph.core.EdgeLabel.class).collect { it.name() },
+    indexes: mgmt.getGraphIndexes(Vertex.class).collect { it.name() }]]
+} finally {
+  mgmt.rollback()
+}`
+
+// readLiveSchema reads the schema element names defined in graph.
+func (driver *GremlinDriver) readLiveSchema(graph string) (*liveSchema, error) {
+	results, err := driver.runScript(fmt.Sprintf(liveSchemaScript, graph))
+	if err != nil {
+		return nil, err
+	}
+	live := &liveSchema{}
+	for _, result := range results {
+		data, ok := result.GetInterface().(map[any]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected schema result %T", result.GetInterface())
+		}
+		live.propertyKeys = appendNames(live.propertyKeys, data["propertyKeys"])
+		live.vertexLabels = appendNames(live.vertexLabels, data["vertexLabels"])
+		live.edgeLabels = appendNames(live.edgeLabels, data["edgeLabels"])
+		live.indexes = appendNames(live.indexes, data["indexes"])
+	}
+	if live.propertyKeys == nil && live.vertexLabels == nil && live.edgeLabels == nil && live.indexes == nil {
+		return nil, errors.New("server returned no schema")
+	}
+	return live, nil
+}
+
+// appendNames adds the strings in the list value to names.
+func appendNames(names map[string]bool, value any) map[string]bool {
+	if names == nil {
+		names = make(map[string]bool)
+	}
+	list, _ := value.([]any)
+	for _, name := range list {
+		if s, ok := name.(string); ok {
+			names[s] = true
+		}
+	}
+	return names
+}
+
+// groovyString quotes s as a single-quoted Groovy string literal.
+func groovyString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
@@ -0,0 +1,245 @@
+package driver_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testJanusUser struct {
+	gsmtypes.Vertex
+	Email   string          `json:"email"   gremlin:"email,unique"`
+	Name    string          `json:"name"    gremlin:"name,index"`
+	Bio     string          `json:"bio"     gremlin:"bio,mixed"`
+	Age     int             `json:"age"     gremlin:"age"`
+	First   string          `json:"first"   gremlin:"first,unique=full_name"`
+	Last    string          `json:"last"    gremlin:"last,unique=full_name"`
+	Tags    []string        `json:"tags"    gremlin:"tags"`
+	Friends []testJanusUser `json:"friends"                         gremlinEdge:"knows"`
+}
+
+type testJanusAccount struct {
+	gsmtypes.Vertex
+	Age string `json:"age" gremlin:"age"`
+}
+
+func liveSchemaResponse(propertyKeys, vertexLabels, edgeLabels, indexes []any) []any {
+	return []any{
+		map[any]any{
+			"propertyKeys": propertyKeys,
+			"vertexLabels": vertexLabels,
+			"edgeLabels":   edgeLabels,
+			"indexes":      indexes,
+		},
+	}
+}
+
+func TestAutoMigrate(t *testing.T) {
+	t.Parallel()
+	janus := driver.Config{Dialect: driver.JanusGraphDialect{}}
+
+	t.Run(
+		"CreatesMissingDefinitions", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{
+					liveSchemaResponse([]any{"created_at", "last_modified"}, []any{}, []any{}, []any{}),
+					{},
+				},
+			}
+			db := driver.NewScriptDriverForTest(janus, server.submit)
+
+			if err := driver.AutoMigrate(db, &testJanusUser{}); err != nil {
+				t.Fatal(err)
+			}
+			if len(server.requests) != 2 {
+				t.Fatalf("Expected a schema read and a migration, got %d requests", len(server.requests))
+			}
+			script := server.requests[1].script
+			for _, want := range []string{
+				"mgmt.makePropertyKey('email').dataType(String.class).cardinality(org.janusgraph.core.Cardinality.SINGLE)",
+				"mgmt.makePropertyKey('age').dataType(Long.class)",
+				"mgmt.makePropertyKey('tags').dataType(String.class).cardinality(org.janusgraph.core.Cardinality.LIST)",
+				"mgmt.makeVertexLabel('test_janus_user').make()",
+				"mgmt.makeEdgeLabel('knows').make()",
+				"mgmt.buildIndex('test_janus_user_email_unique', Vertex.class).addKey(mgmt.getPropertyKey('email'))" +
+					".indexOnly(mgmt.getVertexLabel('test_janus_user')).unique().buildCompositeIndex()",
+				"mgmt.buildIndex('test_janus_user_name_idx', Vertex.class)",
+				"mgmt.buildIndex('test_janus_user_full_name_unique', Vertex.class).addKey(mgmt.getPropertyKey('first'))" +
+					".addKey(mgmt.getPropertyKey('last')).indexOnly(mgmt.getVertexLabel('test_janus_user'))" +
+					".unique().buildCompositeIndex()",
+				".buildMixedIndex('search')",
+				"mgmt.setConsistency(index, org.janusgraph.core.schema.ConsistencyModifier.LOCK)",
+				"mgmt.commit()",
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected script to contain %s, got\n%s", want, script)
+				}
+			}
+			if strings.Contains(script, "'created_at'") || strings.Contains(script, "'id'") {
+				t.Errorf("Expected existing and id keys to be skipped, got\n%s", script)
+			}
+			if strings.Contains(script, "REINDEX") {
+				t.Errorf("Expected indexes over new keys not to be reindexed, got\n%s", script)
+			}
+		},
+	)
+
+	t.Run(
+		"ReindexesExistingKeys", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{
+					liveSchemaResponse(
+						[]any{"created_at", "last_modified", "email", "name"},
+						[]any{"test_janus_user"}, []any{}, []any{},
+					),
+					{},
+				},
+			}
+			db := driver.NewScriptDriverForTest(janus, server.submit)
+
+			if err := driver.AutoMigrate(db, &testJanusUser{}); err != nil {
+				t.Fatal(err)
+			}
+			script := server.requests[1].script
+			const management = "org.janusgraph.graphdb.database.management.ManagementSystem"
+			for _, name := range []string{"test_janus_user_email_unique", "test_janus_user_name_idx"} {
+				for _, want := range []string{
+					management + ".awaitGraphIndexStatus(graph, '" + name + "').call()",
+					"mgmt.updateIndex(mgmt.getGraphIndex('" + name + "'), org.janusgraph.core.schema.SchemaAction.REINDEX).get()",
+					management + ".awaitGraphIndexStatus(graph, '" + name +
+						"').status(org.janusgraph.core.schema.SchemaStatus.ENABLED).call()",
+				} {
+					if !strings.Contains(script, want) {
+						t.Errorf("Expected script to contain %s, got\n%s", want, script)
+					}
+				}
+			}
+			if strings.Contains(script, "getGraphIndex('test_janus_user_bio_mixed')") {
+				t.Errorf("Expected the index over the new bio key not to be reindexed, got\n%s", script)
+			}
+		},
+	)
+
+	t.Run(
+		"TimestampKeys", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{liveSchemaResponse([]any{}, []any{}, []any{}, []any{}), {}},
+			}
+			db := driver.NewScriptDriverForTest(
+				driver.Config{
+					Dialect: driver.JanusGraphDialect{},
+					Timestamps: driver.Timestamps{
+						CreatedAt:    "createdAt",
+						LastModified: "updatedAt",
+						Format:       driver.TimestampEpochMillis,
+					},
+				},
+				server.submit,
+			)
+
+			if err := driver.AutoMigrate(db, &testJanusUser{}, &testArchivedPost{}, &testUntimedTopic{}); err != nil {
+				t.Fatal(err)
+			}
+			script := server.requests[1].script
+			for _, want := range []string{
+				"mgmt.makePropertyKey('createdAt').dataType(Long.class)",
+				"mgmt.makePropertyKey('updatedAt').dataType(Long.class)",
+				"mgmt.makePropertyKey('deleted_at').dataType(Long.class)",
+			} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected script to contain %s, got\n%s", want, script)
+				}
+			}
+			if strings.Contains(script, "'created_at'") || strings.Contains(script, "'last_modified'") {
+				t.Errorf("Expected no default timestamp keys, got\n%s", script)
+			}
+		},
+	)
+
+	t.Run(
+		"UpToDate", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{
+					liveSchemaResponse(
+						[]any{"created_at", "last_modified", "email", "name", "bio", "age", "first", "last", "tags"},
+						[]any{"test_janus_user"},
+						[]any{"knows"},
+						[]any{
+							"test_janus_user_email_unique",
+							"test_janus_user_name_idx",
+							"test_janus_user_bio_mixed",
+							"test_janus_user_full_name_unique",
+						},
+					),
+				},
+			}
+			db := driver.NewScriptDriverForTest(janus, server.submit)
+
+			if err := driver.AutoMigrate(db, testJanusUser{}); err != nil {
+				t.Fatal(err)
+			}
+			if len(server.requests) != 1 {
+				t.Errorf("Expected only the schema read, got %d requests", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"DryRun", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{liveSchemaResponse([]any{}, []any{}, []any{}, []any{})},
+			}
+			db := driver.NewScriptDriverForTest(janus, server.submit)
+
+			var out bytes.Buffer
+			opts := driver.AutoMigrateOptions{DryRun: true, Output: &out, Graph: "users", MixedIndexBackend: "es"}
+			if err := driver.AutoMigrateWithOptions(db, opts, &testJanusUser{}); err != nil {
+				t.Fatal(err)
+			}
+			if len(server.requests) != 1 {
+				t.Errorf("Expected only the schema read, got %d requests", len(server.requests))
+			}
+			if !strings.HasPrefix(out.String(), "mgmt = users.openManagement()") ||
+				!strings.Contains(out.String(), ".buildMixedIndex('es')") {
+				t.Errorf("Unexpected dry-run script\n%s", out.String())
+			}
+		},
+	)
+
+	t.Run(
+		"ConflictingPropertyTypes", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(janus, server.submit)
+
+			err := driver.AutoMigrate(db, &testJanusUser{}, &testJanusAccount{})
+			if err == nil || !strings.Contains(err.Error(), `property "age"`) {
+				t.Errorf("Expected a conflicting property error, got %v", err)
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"UnsupportedDialect", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			err := driver.AutoMigrate(db, &testJanusUser{})
+			if err == nil || !strings.Contains(err.Error(), "not supported by tinkergraph") {
+				t.Errorf("Expected an unsupported dialect error, got %v", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,222 @@
+package driver_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testCosmosUser struct {
+	gsmtypes.Vertex
+	Tenant string   `json:"tenant" gremlin:"tenant,partitionkey"`
+	Name   string   `json:"name"   gremlin:"name"`
+	Tags   []string `json:"tags"   gremlin:"tags"`
+}
+
+type recordedRequest struct {
+	script      string
+	bindings    map[string]any
+	requestArgs map[string]any
+}
+
+// recordedServer stands in for a script-only server: each request is
+// answered with the next recorded response and kept for inspection.
+type recordedServer struct {
+	mu        sync.Mutex
+	responses [][]any
+	requests  []recordedRequest
+}
+
+func (s *recordedServer) submit(
+	script string,
+	bindings map[string]any,
+	requestArgs map[string]any,
+) ([]*gremlingo.Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, recordedRequest{script: script, bindings: bindings, requestArgs: requestArgs})
+	if len(s.responses) == 0 {
+		return nil, errors.New("recordedServer: no response recorded")
+	}
+	response := s.responses[0]
+	s.responses = s.responses[1:]
+	results := make([]*gremlingo.Result, len(response))
+	for i, data := range response {
+		results[i] = &gremlingo.Result{Data: data}
+	}
+	return results, nil
+}
+
+// bindingValues returns the bound values in no particular order.
+func (r recordedRequest) bindingValues() []any {
+	values := make([]any, 0, len(r.bindings))
+	for _, value := range r.bindings {
+		values = append(values, value)
+	}
+	return values
+}
+
+func newCosmosTestDriver(responses ...[]any) (*driver.GremlinDriver, *recordedServer) {
+	server := &recordedServer{responses: responses}
+	db := driver.NewScriptDriverForTest(
+		driver.Config{Dialect: driver.CosmosDBDialect{}},
+		server.submit,
+	)
+	return db, server
+}
+
+func TestCosmosScriptMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"CreateSubmitsScriptWithPartitionKey", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver([]any{"generated-id"})
+			user := testCosmosUser{Tenant: "acme", Name: "O'Brien", Tags: []string{"a", "b"}}
+			if err := driver.Create(db, &user); err != nil {
+				t.Fatal(err)
+			}
+			if user.ID != "generated-id" {
+				t.Errorf("Expected id generated-id, got %v", user.ID)
+			}
+			request := server.requests[0]
+			if !strings.HasPrefix(request.script, "g.addV(p0)") || !strings.HasSuffix(request.script, ".id()") {
+				t.Errorf("Unexpected create script %s", request.script)
+			}
+			if strings.Contains(request.script, "O'Brien") || strings.Contains(request.script, "acme") {
+				t.Errorf("Expected literals to be bound, got %s", request.script)
+			}
+			values := request.bindingValues()
+			for _, want := range []any{"tenant", "acme", "O'Brien"} {
+				if !slices.Contains(values, want) {
+					t.Errorf("Expected binding %v in %v", want, values)
+				}
+			}
+			if strings.Count(request.script, "property(list,") != 2 {
+				t.Errorf("Expected tags written with list cardinality, got %s", request.script)
+			}
+		},
+	)
+
+	t.Run(
+		"CreateRequiresPartitionKey", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver()
+			err := driver.Create(db, &testCosmosUser{Name: "Ann"})
+			if err == nil || !strings.Contains(err.Error(), `partition key "tenant"`) {
+				t.Errorf("Expected missing partition key error, got %v", err)
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"FindUnfoldsValueMapClientSide", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver(
+				[]any{
+					map[any]any{
+						"id":     "1",
+						"label":  "test_cosmos_user",
+						"tenant": []any{"acme"},
+						"name":   []any{"Ann"},
+						"tags":   []any{"x"},
+					},
+				},
+			)
+			users, err := driver.Model[testCosmosUser](db).
+				PartitionKey("acme").
+				Where("name", comparator.EQ, "Ann").
+				Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(users) != 1 || users[0].ID != "1" || users[0].Name != "Ann" || users[0].Tenant != "acme" {
+				t.Fatalf("Unexpected users %+v", users)
+			}
+			if !slices.Equal(users[0].Tags, []string{"x"}) {
+				t.Errorf("Expected tags [x], got %v", users[0].Tags)
+			}
+			request := server.requests[0]
+			if !strings.HasPrefix(request.script, "g.V().hasLabel(p0).has(p1, p2)") {
+				t.Errorf("Expected partition key filter after label, got %s", request.script)
+			}
+			if request.bindings["p1"] != "tenant" || request.bindings["p2"] != "acme" {
+				t.Errorf("Unexpected partition key bindings %v", request.bindings)
+			}
+			if strings.Contains(request.script, ".by(") {
+				t.Errorf("Expected valueMap by() modulator to be dropped, got %s", request.script)
+			}
+		},
+	)
+
+	t.Run(
+		"SaveScopesUpdateToPartition", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver([]any{"1"})
+			user := testCosmosUser{Tenant: "acme", Name: "Ann"}
+			user.ID = "1"
+			if err := driver.Save(db, &user); err != nil {
+				t.Fatal(err)
+			}
+			request := server.requests[0]
+			if !strings.HasPrefix(request.script, "g.V(p0).hasLabel(p1).has(p2, p3)") {
+				t.Errorf("Expected update scoped to partition, got %s", request.script)
+			}
+			if request.bindings["p2"] != "tenant" || request.bindings["p3"] != "acme" {
+				t.Errorf("Unexpected partition key bindings %v", request.bindings)
+			}
+		},
+	)
+
+	t.Run(
+		"TakeEmptyIsNotFound", func(t *testing.T) {
+			t.Parallel()
+			db, _ := newCosmosTestDriver([]any{})
+			_, err := driver.Model[testCosmosUser](db).Take()
+			if !errors.Is(err, gsmtypes.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"Count", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver([]any{int64(3)})
+			count, err := driver.Model[testCosmosUser](db).PartitionKey("acme").Count()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != 3 {
+				t.Errorf("Expected count 3, got %d", count)
+			}
+			if !strings.HasSuffix(server.requests[0].script, ".count()") {
+				t.Errorf("Unexpected count script %s", server.requests[0].script)
+			}
+		},
+	)
+
+	t.Run(
+		"PartitionKeyRequiresTag", func(t *testing.T) {
+			t.Parallel()
+			db, server := newCosmosTestDriver()
+			_, err := driver.Model[testFeedPost](db).PartitionKey("acme").Find()
+			if err == nil || !strings.Contains(err.Error(), "no partitionkey field") {
+				t.Errorf("Expected missing partitionkey field error, got %v", err)
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+}
@@ -2,8 +2,10 @@ package driver
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"reflect"
-	"time"
+	"slices"
 
 	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
 	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
@@ -28,8 +30,13 @@ func updateVertex[T any](db *GremlinDriver, value *T) error {
 	if !ok {
 		return errors.New("value does not implement VertexType")
 	}
-	now := time.Now().UTC()
-	vertex.SetVertexLastModified(now)
+	timestamped := !schemaFor(reflect.TypeFor[T]()).noTimestamps
+	if timestamped {
+		vertex.SetVertexLastModified(db.timestamps.now())
+	}
+	if err := db.validator.Validate(value); err != nil {
+		return err
+	}
 	err := runBeforeUpdateHook(db, value)
 	if err != nil {
 		return err
@@ -45,17 +52,51 @@ func updateVertex[T any](db *GremlinDriver, value *T) error {
 	}
 	id := mapValue["id"]
 	delete(mapValue, "id")
+	db.timestamps.encode(mapValue, timestamped)
 	label := getLabelFromVertex(value)
-	query := db.g.V(id).HasLabel(label)
-	if slicePropertyNames := getSlicePropertyNames(mapValue); len(slicePropertyNames) > 0 {
-		// Drop existing multi-valued properties in the same traversal so
-		// stale elements don't survive the update. This only targets slice
-		// properties: Properties() with no arguments would match (and drop)
-		// every property on the vertex.
-		query = query.SideEffect(anonymousTraversal.Properties(slicePropertyNames...).Drop())
-	}
-	query = handlePropertyUpdate(db, mapValue, query)
-	_, err = query.Next()
+	groups := uniqueGroupsWithValues(schemaFor(reflect.TypeFor[T]()).uniqueGroups, mapValue)
+	auditable := schemaFor(reflect.TypeFor[T]()).auditable
+	err = db.auditedWrite(
+		auditable, func(db *GremlinDriver) ([]AuditRecord, error) {
+			query := db.g.V(id).HasLabel(label)
+			if len(groups) > 0 {
+				query = anonymousTraversal.V(id).HasLabel(label)
+			}
+			if pkName, pkValue, ok := partitionKeyOf(value); ok && pkValue != nil {
+				query = query.Has(pkName, pkValue)
+			}
+			if slicePropertyNames := getSlicePropertyNames(mapValue); len(slicePropertyNames) > 0 {
+				// Drop existing multi-valued properties in the same traversal
+				// so stale elements don't survive the update. This only
+				// targets slice properties: Properties() with no arguments
+				// would match (and drop) every property on the vertex.
+				query = query.SideEffect(anonymousTraversal.Properties(slicePropertyNames...).Drop())
+			}
+			query = handlePropertyUpdate(db, mapValue, query)
+			if len(groups) > 0 {
+				query = uniqueGuard(db, label, groups, mapValue, id, query)
+			}
+			var old map[string]any
+			if auditable {
+				before, err := db.auditSnapshot(db.g.V(id).HasLabel(label), slices.Collect(maps.Keys(mapValue)))
+				if err != nil {
+					return nil, err
+				}
+				if len(before) > 0 {
+					old = before[0].properties
+				}
+			}
+			result, err := db.next(query)
+			if err != nil {
+				return nil, err
+			}
+			if err = uniqueViolation(groups, result); err != nil {
+				return nil, err
+			}
+			changes := db.auditChanges(old, mapValue)
+			return []AuditRecord{{Operation: AuditUpdate, Label: label, VertexID: id, Changes: changes}}, nil
+		},
+	)
 	if err != nil {
 		return err
 	}
@@ -67,34 +108,68 @@ func createVertex[T any](db *GremlinDriver, value *T) error {
 	if !ok {
 		return errors.New("value does not implement VertexType")
 	}
-	now := time.Now().UTC()
-	vertex.SetVertexCreatedAt(now)
-	vertex.SetVertexLastModified(now)
+	timestamped := !schemaFor(reflect.TypeFor[T]()).noTimestamps
+	if timestamped {
+		now := db.timestamps.now()
+		vertex.SetVertexCreatedAt(now)
+		vertex.SetVertexLastModified(now)
+	}
+	if err := db.validator.Validate(value); err != nil {
+		return err
+	}
 	err := runBeforeCreateHook(db, value)
 	if err != nil {
 		return err
 	}
+	if pkName, pkValue, ok := partitionKeyOf(value); ok && pkValue == nil {
+		return fmt.Errorf("partition key %q must be set", pkName)
+	}
 	mapValue, err := structToMap(value)
 	if err != nil {
 		return err
 	}
 	delete(mapValue, "id")
+	db.timestamps.encode(mapValue, timestamped)
 	var hasID bool
 	var id any
 	if db.idGenerator != nil {
 		id = db.idGenerator()
 		if id != nil {
+			id = db.dialect.NormalizeID(id)
 			hasID = true
 		}
 	}
 
 	label := getLabelFromVertex(value)
-	query := db.g.AddV(label)
-	query = handlePropertyUpdate(db, mapValue, query)
-	if hasID {
-		query = query.Property(gremlingo.T.Id, id)
-	}
-	vertexID, err := query.Id().Next()
+	groups := uniqueGroupsWithValues(schemaFor(reflect.TypeFor[T]()).uniqueGroups, mapValue)
+	var vertexID *gremlingo.Result
+	err = db.auditedWrite(
+		schemaFor(reflect.TypeFor[T]()).auditable, func(db *GremlinDriver) ([]AuditRecord, error) {
+			query := db.g.AddV(label)
+			if len(groups) > 0 {
+				// The write runs inside the uniqueness guard, so it must be
+				// anonymous.
+				query = anonymousTraversal.AddV(label)
+			}
+			query = handlePropertyUpdate(db, mapValue, query)
+			if hasID {
+				query = query.Property(gremlingo.T.Id, id)
+			}
+			query = query.Id()
+			if len(groups) > 0 {
+				query = uniqueGuard(db, label, groups, mapValue, nil, query)
+			}
+			var err error
+			if vertexID, err = db.next(query); err != nil {
+				return nil, err
+			}
+			if err = uniqueViolation(groups, vertexID); err != nil {
+				return nil, err
+			}
+			changes := db.auditChanges(nil, mapValue)
+			return []AuditRecord{{Operation: AuditCreate, Label: label, VertexID: vertexID.GetInterface(), Changes: changes}}, nil
+		},
+	)
 	if err != nil {
 		return err
 	}
@@ -102,19 +177,17 @@ func createVertex[T any](db *GremlinDriver, value *T) error {
 	return runAfterCreateHook(db, value)
 }
 
+// handlePropertyUpdate appends a Property step per property. Slice elements
+// are written individually with the dialect's slice cardinality.
 func handlePropertyUpdate(
 	db *GremlinDriver, properties map[string]any, query *gremlingo.GraphTraversal,
 ) *gremlingo.GraphTraversal {
 	for k, v := range properties {
-		// check if v is a slice and Neptune is being used
-		if db.dbDriver == Neptune &&
-			reflect.ValueOf(v).Kind() == reflect.Slice {
-			for i := range reflect.ValueOf(v).Len() {
-				query = query.Property(
-					gremlingo.Cardinality.Set,
-					k,
-					reflect.ValueOf(v).Index(i).Interface(),
-				)
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice {
+			sliceCardinality := db.dialect.SliceCardinality()
+			for i := range rv.Len() {
+				query = query.Property(sliceCardinality, k, rv.Index(i).Interface())
 			}
 		} else {
 			query = query.Property(gremlingo.Cardinality.Single, k, v)
@@ -0,0 +1,206 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// Feature names an optional capability of a graph database.
+type Feature string
+
+const (
+	// FeatureTransactions reports support for sessions with commit/rollback.
+	FeatureTransactions Feature = "transactions"
+	// FeatureMergeV reports support for the mergeV() step. Traversals using
+	// it fail with ErrUnsupported on dialects without it.
+	FeatureMergeV Feature = "mergeV"
+	// FeatureMergeE reports support for the mergeE() step. Traversals using
+	// it fail with ErrUnsupported on dialects without it.
+	FeatureMergeE Feature = "mergeE"
+	// FeatureTextPredicates reports support for TextP predicates.
+	// comparator.CONTAINS and traversals using TextP fail with
+	// ErrUnsupported on dialects without it.
+	FeatureTextPredicates Feature = "textP"
+	// FeatureBytecode reports support for bytecode requests. Without it the
+	// driver submits parameterized scripts instead.
+	FeatureBytecode Feature = "bytecode"
+	// FeatureValueMapBy reports support for by() modulators on valueMap().
+	FeatureValueMapBy Feature = "valueMapBy"
+	// FeatureSchemaManagement reports support for the JanusGraph management
+	// API used by AutoMigrate.
+	FeatureSchemaManagement Feature = "schemaManagement"
+	// FeatureStrategies reports support for the PartitionStrategy,
+	// ReadOnlyStrategy and SubgraphStrategy traversal strategies.
+	FeatureStrategies Feature = "strategies"
+)
+
+// Dialect describes the behaviour of a specific Gremlin-compatible graph
+// database. Select one with Config.Dialect; custom dialects can embed a
+// built-in one and override individual methods:
+//
+//	type myDialect struct{ driver.JanusGraphDialect }
+//
+//	func (myDialect) MaxBatchSize() int { return 250 }
+type Dialect interface {
+	// Name identifies the dialect in logs and errors.
+	Name() string
+	// SliceCardinality returns the cardinality used to write each element
+	// of a slice property, gremlingo.Cardinality.List or
+	// gremlingo.Cardinality.Set.
+	SliceCardinality() any
+	// NormalizeID converts a vertex id into the type the database expects
+	// before it is written or looked up.
+	NormalizeID(id any) any
+	// Supports reports whether the database supports feature.
+	Supports(feature Feature) bool
+	// TextContains returns the predicate used for comparator.CONTAINS.
+	TextContains(value string) gremlingo.TextPredicate
+	// MaxBatchSize is the maximum number of elements written by a single
+	// batched traversal, such as those of the migrate helpers, which never
+	// exceed it. Zero means unlimited.
+	MaxBatchSize() int
+}
+
+// TinkerGraphDialect targets Apache TinkerPop Gremlin Server backed by
+// TinkerGraph. Transactions are reported as supported because
+// TinkerTransactionGraph provides them; plain TinkerGraph rejects them
+// server-side.
+type TinkerGraphDialect struct{}
+
+func (TinkerGraphDialect) Name() string           { return "tinkergraph" }
+func (TinkerGraphDialect) SliceCardinality() any  { return gremlingo.Cardinality.List }
+func (TinkerGraphDialect) NormalizeID(id any) any { return id }
+func (TinkerGraphDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureTransactions, FeatureMergeV, FeatureMergeE, FeatureTextPredicates,
+		FeatureBytecode, FeatureValueMapBy, FeatureStrategies:
+		return true
+	}
+	return false
+}
+
+func (TinkerGraphDialect) TextContains(value string) gremlingo.TextPredicate {
+	return gremlingo.TextP.Containing(value)
+}
+func (TinkerGraphDialect) MaxBatchSize() int { return 0 }
+
+// JanusGraphDialect targets JanusGraph.
+type JanusGraphDialect struct{}
+
+func (JanusGraphDialect) Name() string           { return "janusgraph" }
+func (JanusGraphDialect) SliceCardinality() any  { return gremlingo.Cardinality.List }
+func (JanusGraphDialect) NormalizeID(id any) any { return id }
+func (JanusGraphDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureTransactions, FeatureMergeV, FeatureMergeE, FeatureTextPredicates,
+		FeatureBytecode, FeatureValueMapBy, FeatureSchemaManagement, FeatureStrategies:
+		return true
+	}
+	return false
+}
+
+func (JanusGraphDialect) TextContains(value string) gremlingo.TextPredicate {
+	return gremlingo.TextP.Containing(value)
+}
+func (JanusGraphDialect) MaxBatchSize() int { return 1000 }
+
+// NeptuneDialect targets Amazon Neptune. Neptune only supports set
+// cardinality for multi-valued properties and string vertex ids.
+type NeptuneDialect struct{}
+
+func (NeptuneDialect) Name() string           { return "neptune" }
+func (NeptuneDialect) SliceCardinality() any  { return gremlingo.Cardinality.Set }
+func (NeptuneDialect) NormalizeID(id any) any { return stringID(id) }
+func (NeptuneDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureTransactions, FeatureMergeV, FeatureMergeE, FeatureTextPredicates,
+		FeatureBytecode, FeatureValueMapBy, FeatureStrategies:
+		return true
+	}
+	return false
+}
+
+func (NeptuneDialect) TextContains(value string) gremlingo.TextPredicate {
+	return gremlingo.TextP.Containing(value)
+}
+func (NeptuneDialect) MaxBatchSize() int { return 100 }
+
+// CosmosDBDialect targets the Azure Cosmos DB Gremlin API, which uses string
+// ids and supports neither transactions nor the merge steps. Cosmos DB only
+// accepts scripts, so the driver translates every traversal into a
+// parameterized script and drops the valueMap() by() modulators it rejects.
+type CosmosDBDialect struct{}
+
+func (CosmosDBDialect) Name() string           { return "cosmosdb" }
+func (CosmosDBDialect) SliceCardinality() any  { return gremlingo.Cardinality.List }
+func (CosmosDBDialect) NormalizeID(id any) any { return stringID(id) }
+func (CosmosDBDialect) Supports(feature Feature) bool {
+	switch feature {
+	case FeatureTextPredicates:
+		return true
+	case FeatureTransactions, FeatureMergeV, FeatureMergeE, FeatureBytecode, FeatureValueMapBy,
+		FeatureStrategies:
+	}
+	return false
+}
+
+func (CosmosDBDialect) TextContains(value string) gremlingo.TextPredicate {
+	return gremlingo.TextP.Containing(value)
+}
+func (CosmosDBDialect) MaxBatchSize() int { return 25 }
+
+// stepFeatures are the steps that need a dialect feature.
+var stepFeatures = map[string]Feature{"mergeV": FeatureMergeV, "mergeE": FeatureMergeE}
+
+// unsupportedStep returns the first step of bytecode, including its child
+// traversals, or "TextP" for the first TextP predicate, that dialect does
+// not support, or "" when there is none.
+func unsupportedStep(bytecode *gremlingo.Bytecode, dialect Dialect) string {
+	textP := dialect.Supports(FeatureTextPredicates)
+	steps := readField(reflect.ValueOf(bytecode).Elem(), "stepInstructions")
+	for i := range steps.Len() {
+		operator, args := readInstruction(steps.Index(i))
+		if feature, ok := stepFeatures[operator]; ok && !dialect.Supports(feature) {
+			return operator + "()"
+		}
+		for _, arg := range args {
+			switch typed := arg.(type) {
+			case *gremlingo.Bytecode:
+				if operator := unsupportedStep(typed, dialect); operator != "" {
+					return operator
+				}
+			case gremlingo.TextPredicate:
+				if !textP {
+					return "TextP"
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// stringID converts non-nil, non-string ids to their string form.
+func stringID(id any) any {
+	switch value := id.(type) {
+	case nil, string:
+		return id
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// dialectFor resolves the dialect from a Config, falling back to the
+// deprecated Driver field.
+func dialectFor(config Config) Dialect {
+	if config.Dialect != nil {
+		return config.Dialect
+	}
+	if config.Driver == Neptune {
+		return NeptuneDialect{}
+	}
+	return TinkerGraphDialect{}
+}
@@ -0,0 +1,229 @@
+package driver_test
+
+import (
+	"errors"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+type testStringerID struct{}
+
+func (testStringerID) String() string { return "stringer-id" }
+
+type testCustomDialect struct {
+	driver.JanusGraphDialect
+}
+
+func (testCustomDialect) MaxBatchSize() int { return 7 }
+
+// testNoTextDialect is a TinkerGraph without TextP predicates.
+type testNoTextDialect struct {
+	driver.TinkerGraphDialect
+}
+
+func (d testNoTextDialect) Supports(feature driver.Feature) bool {
+	return feature != driver.FeatureTextPredicates && d.TinkerGraphDialect.Supports(feature)
+}
+
+func TestDialectFor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		config   driver.Config
+		wantName string
+	}{
+		{name: "default", config: driver.Config{}, wantName: "tinkergraph"},
+		{name: "deprecated gremlin driver", config: driver.Config{Driver: driver.Gremlin}, wantName: "tinkergraph"},
+		{name: "deprecated neptune driver", config: driver.Config{Driver: driver.Neptune}, wantName: "neptune"},
+		{
+			name:     "dialect wins over driver",
+			config:   driver.Config{Driver: driver.Neptune, Dialect: driver.JanusGraphDialect{}},
+			wantName: "janusgraph",
+		},
+		{name: "cosmos", config: driver.Config{Dialect: driver.CosmosDBDialect{}}, wantName: "cosmosdb"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				if got := driver.DialectForTest(tt.config).Name(); got != tt.wantName {
+					t.Errorf("Expected dialect %s, got %s", tt.wantName, got)
+				}
+			},
+		)
+	}
+}
+
+func TestBuiltInDialects(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		dialect          driver.Dialect
+		wantCardinality  any
+		wantTransactions bool
+		wantMergeV       bool
+		wantStringIDs    bool
+		wantBytecode     bool
+		wantSchema       bool
+		wantStrategies   bool
+	}{
+		{
+			dialect:          driver.TinkerGraphDialect{},
+			wantCardinality:  gremlingo.Cardinality.List,
+			wantTransactions: true,
+			wantMergeV:       true,
+			wantBytecode:     true,
+			wantStrategies:   true,
+		},
+		{
+			dialect:          driver.JanusGraphDialect{},
+			wantCardinality:  gremlingo.Cardinality.List,
+			wantTransactions: true,
+			wantMergeV:       true,
+			wantBytecode:     true,
+			wantSchema:       true,
+			wantStrategies:   true,
+		},
+		{
+			dialect:          driver.NeptuneDialect{},
+			wantCardinality:  gremlingo.Cardinality.Set,
+			wantTransactions: true,
+			wantMergeV:       true,
+			wantStringIDs:    true,
+			wantBytecode:     true,
+			wantStrategies:   true,
+		},
+		{
+			dialect:         driver.CosmosDBDialect{},
+			wantCardinality: gremlingo.Cardinality.List,
+			wantStringIDs:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.dialect.Name(), func(t *testing.T) {
+				t.Parallel()
+				if got := tt.dialect.SliceCardinality(); got != tt.wantCardinality {
+					t.Errorf("Expected slice cardinality %v, got %v", tt.wantCardinality, got)
+				}
+				if got := tt.dialect.Supports(driver.FeatureTransactions); got != tt.wantTransactions {
+					t.Errorf("Expected transactions support %v, got %v", tt.wantTransactions, got)
+				}
+				if got := tt.dialect.Supports(driver.FeatureMergeV); got != tt.wantMergeV {
+					t.Errorf("Expected mergeV support %v, got %v", tt.wantMergeV, got)
+				}
+				if got := tt.dialect.Supports(driver.FeatureBytecode); got != tt.wantBytecode {
+					t.Errorf("Expected bytecode support %v, got %v", tt.wantBytecode, got)
+				}
+				if got := tt.dialect.Supports(driver.FeatureSchemaManagement); got != tt.wantSchema {
+					t.Errorf("Expected schema management support %v, got %v", tt.wantSchema, got)
+				}
+				if got := tt.dialect.Supports(driver.FeatureStrategies); got != tt.wantStrategies {
+					t.Errorf("Expected strategies support %v, got %v", tt.wantStrategies, got)
+				}
+				if tt.dialect.TextContains("x") == nil {
+					t.Error("Expected a text contains predicate")
+				}
+				if tt.dialect.MaxBatchSize() < 0 {
+					t.Errorf("Expected non-negative max batch size, got %d", tt.dialect.MaxBatchSize())
+				}
+
+				id := tt.dialect.NormalizeID(42)
+				if tt.wantStringIDs {
+					if id != "42" {
+						t.Errorf("Expected id to be normalized to \"42\", got %v", id)
+					}
+					if got := tt.dialect.NormalizeID(testStringerID{}); got != "stringer-id" {
+						t.Errorf("Expected Stringer id to be normalized, got %v", got)
+					}
+				} else if id != 42 {
+					t.Errorf("Expected id to be left unchanged, got %v", id)
+				}
+				if got := tt.dialect.NormalizeID(nil); got != nil {
+					t.Errorf("Expected nil id to stay nil, got %v", got)
+				}
+			},
+		)
+	}
+}
+
+func TestCustomDialectEmbedding(t *testing.T) {
+	t.Parallel()
+	var dialect driver.Dialect = testCustomDialect{}
+	if dialect.MaxBatchSize() != 7 {
+		t.Errorf("Expected overridden max batch size 7, got %d", dialect.MaxBatchSize())
+	}
+	if dialect.Name() != "janusgraph" {
+		t.Errorf("Expected embedded name janusgraph, got %s", dialect.Name())
+	}
+}
+
+func TestUnsupportedFeatures(t *testing.T) {
+	t.Parallel()
+	__ := gremlingo.T__
+	tests := []struct {
+		name    string
+		dialect driver.Dialect
+		run     func(db *driver.GremlinDriver) error
+		wantErr bool
+	}{
+		{
+			name:    "MergeV",
+			dialect: driver.CosmosDBDialect{},
+			run: func(db *driver.GremlinDriver) error {
+				return db.Iterate(db.G().MergeV(map[any]any{gremlingo.T.Label: "test_topic"}))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "ChildMergeE",
+			dialect: driver.CosmosDBDialect{},
+			run: func(db *driver.GremlinDriver) error {
+				return db.Iterate(db.G().V("1").SideEffect(__.MergeE(map[any]any{gremlingo.T.Label: "knows"})))
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Contains",
+			dialect: testNoTextDialect{},
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testTopic](db).Where("title", comparator.CONTAINS, "graph").Find()
+				return err
+			},
+			wantErr: true,
+		},
+		{
+			name:    "Supported",
+			dialect: driver.TinkerGraphDialect{},
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testTopic](db).Where("title", comparator.CONTAINS, "graph").Find()
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{}}}
+				db := driver.NewScriptDriverForTest(driver.Config{Dialect: tt.dialect}, server.submit)
+
+				err := tt.run(db)
+				if !tt.wantErr {
+					if err != nil {
+						t.Fatal(err)
+					}
+					return
+				}
+				if !errors.Is(err, driver.ErrUnsupported) {
+					t.Errorf("Expected ErrUnsupported, got %v", err)
+				}
+				if len(server.requests) != 0 {
+					t.Errorf("Expected no requests, got %d", len(server.requests))
+				}
+			},
+		)
+	}
+}
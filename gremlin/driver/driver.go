@@ -1,8 +1,10 @@
 package driver
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
 	"github.com/charmbracelet/log"
@@ -11,6 +13,9 @@ import (
 	appLogger "github.com/jbrusegaard/graph-struct-manager/log"
 )
 
+// DatabaseDriver selects the database flavour.
+//
+// Deprecated: set Config.Dialect instead.
 type DatabaseDriver string
 
 const (
@@ -20,12 +25,48 @@ const (
 
 type GremlinDriver struct {
 	remoteConn  *gremlingo.DriverRemoteConnection
+	connection  *connectionState
 	g           *gremlingo.GraphTraversalSource
 	logger      *log.Logger
-	dbDriver    DatabaseDriver
+	dialect     Dialect
 	idGenerator func() any
+	// scripts is non-nil in script mode, where traversals are translated
+	// to parameterized scripts instead of being submitted as bytecode
+	scripts scriptSubmitter
+	// readers serve Find, Take, Count and ID when read replicas are
+	// configured; nil otherwise and in transactions
+	readers *readerPool
+	// retryPolicy retries transient failures; nil when retries are disabled
+	// and in transactions, which are retried as a whole
+	retryPolicy *RetryPolicy
+	// queryTimeout is the default evaluation timeout; zero leaves it to the
+	// server
+	queryTimeout time.Duration
+	guardrails   Guardrails
+	// validator checks models before Create, Save and Updates write them
+	validator Validator
+	// healthCheck pings the endpoints in the background; nil when disabled
+	healthCheck *healthCheck
 	// tx is non-nil when this driver is bound to an open transaction
 	tx *gremlingo.Transaction
+	// tenantKey is the partition key property used by ForTenant
+	tenantKey string
+	// injected are the steps ForTenant and WithSubgraph views inject into
+	// every traversal when the dialect has no FeatureStrategies
+	injected *injectedSteps
+	// readOnly rejects traversals that modify the graph
+	readOnly bool
+	// timestamps configures the created and last modified timestamps
+	timestamps timestamps
+	// auditSink receives the audit records of Auditable models; nil writes
+	// them to the graph
+	auditSink AuditSink
+	// ctx is the context set by WithContext, or nil
+	ctx context.Context
+	// isView is set on drivers derived by ForTenant, ReadOnly,
+	// WithSubgraph and WithContext, which share the connections of the driver they were
+	// derived from
+	isView bool
 }
 
 type QueryOpts struct {
@@ -34,13 +75,65 @@ type QueryOpts struct {
 }
 
 type Config struct {
-	Driver                    DatabaseDriver
+	// Driver selects between the TinkerGraph and Neptune dialects.
+	//
+	// Deprecated: set Dialect instead. Driver is ignored when Dialect is set.
+	Driver DatabaseDriver
+	// Dialect describes the backing graph database (default TinkerGraphDialect).
+	Dialect Dialect
+	// ScriptMode submits every traversal as a Gremlin script with its literal
+	// values lifted into bindings instead of as bytecode, for servers and
+	// proxies that only accept scripts. It is implied by dialects that do
+	// not support FeatureBytecode. Transactions are unavailable in script mode.
+	ScriptMode                bool
 	IDGenerator               func() any
 	GremlinConnectionSettings func(settings *gremlingo.DriverRemoteConnectionSettings)
+	// IAMAuth signs every connection with AWS SigV4 for IAM-enabled Neptune
+	// clusters. It overrides any AuthInfo set by GremlinConnectionSettings.
+	IAMAuth *IAMAuthConfig
+	// ReaderURLs are read replica endpoints, given in the same form as the
+	// writer url passed to Open. Find, Take, Count and ID are spread across
+	// them round-robin; writes and transactions always use the writer.
+	ReaderURLs []string
+	// Retry retries reads and transactions that fail with transient errors
+	// such as concurrent modifications or dropped connections.
+	Retry *RetryPolicy
+	// QueryTimeout bounds every request on the server, through its
+	// evaluationTimeout, and on the client. Query.Timeout overrides it per
+	// query. Zero leaves the server's evaluationTimeout in charge.
+	QueryTimeout time.Duration
+	// Guardrails refuse or flag queries that would scan or modify a whole
+	// label by mistake. The zero value disables them.
+	Guardrails Guardrails
+	// Validator checks models before Create, Save and Updates write them.
+	// The default TagValidator applies gsmvalidate struct tags.
+	Validator Validator
+	// PingOnOpen makes Open fail unless the writer and every reader answer
+	// a ping, catching authentication and traversal source errors that the
+	// websocket handshake alone does not.
+	PingOnOpen bool
+	// HealthCheckInterval pings the writer and readers in the background at
+	// this interval. Zero disables the health check.
+	HealthCheckInterval time.Duration
+	// OnConnectionStateChange is called whenever the writer or a reader goes
+	// down or comes back up.
+	OnConnectionStateChange func(ConnectionEvent)
+	// Clock returns the time written to created and last modified
+	// timestamps (default time.Now), so tests can control it.
+	Clock func() time.Time
+	// Timestamps configures the names and format of the created and last
+	// modified timestamps.
+	Timestamps Timestamps
+	// TenantKey is the property holding the tenant of each vertex and edge
+	// written through ForTenant (default "tenant_id").
+	TenantKey string
+	// AuditSink receives the audit records of Auditable models. By default
+	// they are written to the graph as AuditLabel vertices.
+	AuditSink AuditSink
 }
 
 var defaultDriverConfig = Config{
-	Driver:                    Gremlin,
+	Dialect:                   TinkerGraphDialect{},
 	IDGenerator:               nil,
 	GremlinConnectionSettings: nil,
 }
@@ -63,32 +156,102 @@ func Open(url string, config ...Config) (*GremlinDriver, error) {
 	} else {
 		configStruct = defaultDriverConfig
 	}
-	if configStruct.GremlinConnectionSettings == nil {
-		remote, err = gremlingo.NewDriverRemoteConnection(fmt.Sprintf("%s/gremlin", url))
-		if err != nil {
-			return nil, err
+	remote, err = openRemote(url, configStruct, driverLogger)
+	if err != nil {
+		return nil, err
+	}
+
+	driver := &GremlinDriver{
+		g:          g(remote),
+		remoteConn: remote,
+		connection: &connectionState{
+			url:      url,
+			logger:   driverLogger,
+			onChange: configStruct.OnConnectionStateChange,
+		},
+		logger:       driverLogger,
+		dialect:      dialectFor(configStruct),
+		idGenerator:  configStruct.IDGenerator,
+		queryTimeout: configStruct.QueryTimeout,
+		guardrails:   configStruct.Guardrails,
+		validator:    validatorFor(configStruct),
+		tenantKey:    tenantKeyFor(configStruct),
+		timestamps:   timestampsFor(configStruct),
+		auditSink:    configStruct.AuditSink,
+	}
+	if configStruct.Retry != nil && configStruct.Retry.MaxAttempts > 1 {
+		policy := configStruct.Retry.withDefaults()
+		driver.retryPolicy = &policy
+	}
+	scriptMode := configStruct.ScriptMode || !driver.dialect.Supports(FeatureBytecode)
+	if scriptMode {
+		driverLogger.Infof("Submitting traversals to %s as scripts", driver.dialect.Name())
+		driver.scripts = remoteScriptSubmitter{remote: remote}
+	}
+	if len(configStruct.ReaderURLs) > 0 {
+		driver.readers = &readerPool{}
+		for _, readerURL := range configStruct.ReaderURLs {
+			driverLogger.Infof("Opening reader with url: %s/gremlin", readerURL)
+			readerRemote, readerErr := openRemote(readerURL, configStruct, driverLogger)
+			if readerErr != nil {
+				driver.Close()
+				return nil, fmt.Errorf("failed to open reader %s: %w", readerURL, readerErr)
+			}
+			r := &reader{
+				url:    readerURL,
+				remote: readerRemote,
+				state: &connectionState{
+					url:      readerURL,
+					logger:   driverLogger,
+					onChange: configStruct.OnConnectionStateChange,
+				},
+			}
+			if scriptMode {
+				r.scripts = remoteScriptSubmitter{remote: readerRemote}
+			}
+			driver.readers.readers = append(driver.readers.readers, r)
 		}
-	} else {
-		remote, err = gremlingo.NewDriverRemoteConnection(
-			fmt.Sprintf("%s/gremlin", url),
-			configStruct.GremlinConnectionSettings,
-		)
-		if err != nil {
+	}
+	if configStruct.PingOnOpen {
+		if err = driver.pingAll(); err != nil {
+			driver.Close()
 			return nil, err
 		}
 	}
-
-	driver := &GremlinDriver{
-		g:           g(remote),
-		remoteConn:  remote,
-		logger:      driverLogger,
-		dbDriver:    configStruct.Driver,
-		idGenerator: configStruct.IDGenerator,
+	if configStruct.HealthCheckInterval > 0 {
+		driver.startHealthCheck(configStruct.HealthCheckInterval)
 	}
 	return driver, nil
 }
 
+// openRemote connects to the gremlin endpoint at url with the connection
+// settings and authentication from config.
+func openRemote(
+	url string,
+	config Config,
+	logger *log.Logger,
+) (*gremlingo.DriverRemoteConnection, error) {
+	endpoint := fmt.Sprintf("%s/gremlin", url)
+	var settings []func(settings *gremlingo.DriverRemoteConnectionSettings)
+	if config.GremlinConnectionSettings != nil {
+		settings = append(settings, config.GremlinConnectionSettings)
+	}
+	if config.IAMAuth != nil {
+		signer, err := newIAMSigner(endpoint, *config.IAMAuth)
+		if err != nil {
+			return nil, err
+		}
+		settings = append(settings, func(settings *gremlingo.DriverRemoteConnectionSettings) {
+			settings.AuthInfo = signer.authInfo(logger.Errorf)
+		})
+	}
+	return gremlingo.NewDriverRemoteConnection(endpoint, settings...)
+}
+
 func (driver *GremlinDriver) Close() {
+	if driver.isView {
+		return
+	}
 	if driver.tx != nil {
 		// A transaction-bound driver owns only its session, not the shared
 		// remote connection. Closing the transaction rolls back if still open.
@@ -97,14 +260,34 @@ func (driver *GremlinDriver) Close() {
 		}
 		return
 	}
+	driver.healthCheck.close()
+	driver.readers.close()
 	driver.remoteConn.Close()
 }
 
+// Dialect returns the dialect describing the backing graph database.
+func (driver *GremlinDriver) Dialect() Dialect {
+	return driver.dialect
+}
+
 // G exposes the traversal source for building custom traversals.
 func (driver *GremlinDriver) G() *gremlingo.GraphTraversalSource {
 	return driver.g
 }
 
+// ToList runs a traversal built from G() and returns every result. Unlike
+// calling ToList on the traversal itself, it goes through script mode,
+// QueryTimeout and error classification like the driver's own queries.
+func (driver *GremlinDriver) ToList(traversal *gremlingo.GraphTraversal) ([]*gremlingo.Result, error) {
+	return driver.toList(traversal)
+}
+
+// Iterate runs a traversal built from G() for its side effects, as ToList
+// does.
+func (driver *GremlinDriver) Iterate(traversal *gremlingo.GraphTraversal) error {
+	return driver.iterate(traversal)
+}
+
 // Label returns a query builder for a specific label
 func (driver *GremlinDriver) Label(label string) *RawQuery {
 	return &RawQuery{
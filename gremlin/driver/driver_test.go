@@ -40,7 +40,7 @@ func TestDriverConnections(t *testing.T) {
 				t.Parallel()
 				db, err := driver.Open(
 					tt.url, driver.Config{
-						Driver: dbDriver,
+						Dialect: dbDialect,
 					},
 				)
 				if (err != nil) != tt.wantErr {
@@ -58,7 +58,7 @@ func TestDriverConnections(t *testing.T) {
 func TestDriverTable(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -77,7 +77,7 @@ func TestDriverTable(t *testing.T) {
 func TestDriverModel(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -93,7 +93,7 @@ func TestDriverModel(t *testing.T) {
 func TestDriverWhere(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -109,7 +109,7 @@ func TestDriverWhere(t *testing.T) {
 func TestDriverSave(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -150,7 +150,7 @@ func (t *testVertexWithCustomLabel2) Label() string {
 func TestDriverSaveWithCustomLabel(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -186,7 +186,7 @@ func (*testVertexWithCustomLabel3) Label() string {
 func TestDriverSaveWithCustomLabel3(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
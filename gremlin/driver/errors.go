@@ -0,0 +1,191 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors that failed requests are classified as. Errors returned by
+// queries wrap one of them where the cause is known, so callers can branch
+// with errors.Is and reach the details with errors.As and *GremlinError.
+var (
+	// ErrConnection means the server could not be reached or the connection
+	// dropped before a response arrived.
+	ErrConnection = errors.New("connection error")
+	// ErrTimeout means the request did not finish in time, either on the
+	// server or before the caller's context deadline.
+	ErrTimeout = errors.New("timeout")
+	// ErrConflict means the request lost a race with a concurrent write,
+	// e.g. Neptune's ConcurrentModificationException or JanusGraph lock
+	// contention. Repeating the request usually succeeds.
+	ErrConflict = errors.New("conflict")
+	// ErrThrottled means the server rejected the request for exceeding its
+	// provisioned throughput, e.g. Cosmos DB's RequestRateTooLarge.
+	ErrThrottled = errors.New("request rate too large")
+	// ErrInvalidQuery means the server could not parse or evaluate the
+	// request.
+	ErrInvalidQuery = errors.New("invalid query")
+	// ErrConstraintViolation means the write breaks a schema or uniqueness
+	// constraint, including an id that already exists.
+	ErrConstraintViolation = errors.New("constraint violation")
+	// ErrTransactionUnsupported is returned by Begin and Transaction when the
+	// dialect, script mode or the server's graph cannot run transactions.
+	ErrTransactionUnsupported = errors.New("transactions are not supported")
+	// ErrReadOnly means a driver returned by ReadOnly was asked to modify
+	// the graph, either before the request was sent or by the server's
+	// ReadOnlyStrategy.
+	ErrReadOnly = errors.New("read-only")
+	// ErrUnsupported means the traversal uses a step or predicate the
+	// dialect does not support (see Dialect.Supports). It is returned
+	// before the request is sent.
+	ErrUnsupported = errors.New("not supported")
+)
+
+// Gremlin Server response status codes.
+const (
+	statusMalformedRequest        = 498
+	statusInvalidRequestArguments = 499
+	statusServerErrorTemporary    = 596
+	statusServerErrorEvaluation   = 597
+	statusServerErrorTimeout      = 598
+)
+
+// GremlinError is a failed request with its classification and the status
+// code of the server response. Error returns the original message.
+type GremlinError struct {
+	// Kind is the sentinel the error was classified as, nil when unknown.
+	Kind error
+	// StatusCode is the Gremlin Server response status code, or 0 when the
+	// request failed before a response arrived.
+	StatusCode int
+	// Err is the error reported by gremlingo.
+	Err error
+}
+
+func (e *GremlinError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *GremlinError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// connectionErrorCodes are the gremlingo error codes raised when a
+// connection cannot be made or was lost.
+var connectionErrorCodes = []string{"E0101:", "E0102:", "E0103:", "E0104:", "E0105:", "E0203:"}
+
+// errorMarkers map exception names and messages found in server errors to
+// their kind. They take precedence over the response status code, which is
+// a generic 500 for most provider exceptions.
+var errorMarkers = []struct {
+	marker string
+	kind   error
+}{
+	// Neptune
+	{"ConcurrentModificationException", ErrConflict},
+	{"ConstraintViolationException", ErrConstraintViolation},
+	{"TimeLimitExceededException", ErrTimeout},
+	{"MalformedQueryException", ErrInvalidQuery},
+	{"InvalidParameterException", ErrInvalidQuery},
+	{"ThrottlingException", ErrThrottled},
+	// JanusGraph
+	{"PermanentLockingException", ErrConflict},
+	{"TemporaryLockingException", ErrConflict},
+	{"TemporaryBackendException", ErrConflict},
+	{"Lock expired", ErrConflict},
+	{"SchemaViolationException", ErrConstraintViolation},
+	{"violates a uniqueness constraint", ErrConstraintViolation},
+	// Cosmos DB
+	{"RequestRateTooLarge", ErrThrottled},
+	// Gremlin Server
+	{"does not support transactions", ErrTransactionUnsupported},
+	{"is not read only", ErrReadOnly},
+}
+
+// cosmosStatusKinds map the HTTP status Cosmos DB reports in the
+// x-ms-status-code response attribute to their kind.
+var cosmosStatusKinds = map[int]error{
+	400: ErrInvalidQuery,
+	408: ErrTimeout,
+	409: ErrConstraintViolation,
+	412: ErrConflict,
+	429: ErrThrottled,
+}
+
+var (
+	statusCodePattern      = regexp.MustCompile(`statusCode: (\d+)$`)
+	cosmosStatusPattern    = regexp.MustCompile(`x-ms-status-code:(\d+)`)
+	gremlinNotFoundMessage = errGremlinNotFound.Error()
+)
+
+// classifyError wraps err in a *GremlinError describing its kind. Errors
+// that are already classified, and errors of unknown kind that did not come
+// from a server response, are returned unchanged. The gremlingo "no results
+// left" error becomes errGremlinNotFound.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var gremlinErr *GremlinError
+	if errors.As(err, &gremlinErr) || errors.Is(err, errGremlinNotFound) {
+		return err
+	}
+	message := err.Error()
+	if message == gremlinNotFoundMessage {
+		return errGremlinNotFound
+	}
+	classified := &GremlinError{Err: err}
+	if match := statusCodePattern.FindStringSubmatch(message); match != nil {
+		classified.StatusCode, _ = strconv.Atoi(match[1])
+	}
+	classified.Kind = errorKind(err, message, classified.StatusCode)
+	if classified.Kind == nil && classified.StatusCode == 0 {
+		return err
+	}
+	return classified
+}
+
+// errorKind returns the sentinel err belongs to, or nil.
+func errorKind(err error, message string, statusCode int) error {
+	// context.DeadlineExceeded also implements net.Error.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrTimeout
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrConnection
+	}
+	for _, code := range connectionErrorCodes {
+		if strings.Contains(message, code) {
+			return ErrConnection
+		}
+	}
+	if strings.Contains(message, "websocket: ") {
+		return ErrConnection
+	}
+	for _, m := range errorMarkers {
+		if strings.Contains(message, m.marker) {
+			return m.kind
+		}
+	}
+	if match := cosmosStatusPattern.FindStringSubmatch(message); match != nil {
+		status, _ := strconv.Atoi(match[1])
+		if kind, ok := cosmosStatusKinds[status]; ok {
+			return kind
+		}
+	}
+	switch statusCode {
+	case statusMalformedRequest, statusInvalidRequestArguments, statusServerErrorEvaluation:
+		return ErrInvalidQuery
+	case statusServerErrorTimeout:
+		return ErrTimeout
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package driver_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+func TestErrorClassification(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		err        error
+		want       error
+		wantStatus int
+	}{
+		{
+			name: "ConnectionRefused",
+			err:  errors.New("E0104: no successful connections could be made: dial tcp 127.0.0.1:8182"),
+			want: driver.ErrConnection,
+		},
+		{
+			name: "ClosedConnection",
+			err:  errors.New("E0203: cannot submit bytecode to closed connection"),
+			want: driver.ErrConnection,
+		},
+		{
+			name:       "NeptuneConcurrentModification",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:{\"code\":\"ConcurrentModificationException\"}}'. statusCode: 500"),
+			want:       driver.ErrConflict,
+			wantStatus: 500,
+		},
+		{
+			name:       "NeptuneConstraintViolation",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:{\"code\":\"ConstraintViolationException\"}}'. statusCode: 500"),
+			want:       driver.ErrConstraintViolation,
+			wantStatus: 500,
+		},
+		{
+			name:       "JanusGraphUniqueness",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:Adding this property for key [email] and value [a@b.c] violates a uniqueness constraint [byEmail]}'. statusCode: 500"),
+			want:       driver.ErrConstraintViolation,
+			wantStatus: 500,
+		},
+		{
+			name:       "ServerTimeout",
+			err:        errors.New("E0502: error in read loop, error message '{code:598 message:evaluation exceeded 30000 ms}'. statusCode: 598"),
+			want:       driver.ErrTimeout,
+			wantStatus: 598,
+		},
+		{
+			name:       "EvaluationError",
+			err:        errors.New("E0502: error in read loop, error message '{code:597 message:No signature of method}'. statusCode: 597"),
+			want:       driver.ErrInvalidQuery,
+			wantStatus: 597,
+		},
+		{
+			name:       "MalformedRequest",
+			err:        errors.New("E0502: error in read loop, error message '{code:498 message:bad request}'. statusCode: 498"),
+			want:       driver.ErrInvalidQuery,
+			wantStatus: 498,
+		},
+		{
+			name:       "CosmosConflict",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:Resource with specified id already exists attributes:map[x-ms-status-code:409]}'. statusCode: 500"),
+			want:       driver.ErrConstraintViolation,
+			wantStatus: 500,
+		},
+		{
+			name:       "CosmosThrottled",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:Request rate is large attributes:map[x-ms-status-code:429]}'. statusCode: 500"),
+			want:       driver.ErrThrottled,
+			wantStatus: 500,
+		},
+		{
+			name:       "GraphWithoutTransactions",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:Graph does not support transactions}'. statusCode: 500"),
+			want:       driver.ErrTransactionUnsupported,
+			wantStatus: 500,
+		},
+		{
+			name:       "ReadOnlyStrategy",
+			err:        errors.New("E0502: error in read loop, error message '{code:500 message:The provided traversal has a mutating step and thus is not read only: [AddVertexStartStep({label=[user]})]}'. statusCode: 500"),
+			want:       driver.ErrReadOnly,
+			wantStatus: 500,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &countingServer{err: tt.err}
+				db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+				_, err := driver.Model[testTopic](db).Find()
+				if !errors.Is(err, tt.want) {
+					t.Errorf("Expected errors.Is(err, %v), got %v", tt.want, err)
+				}
+				if !errors.Is(err, tt.err) {
+					t.Errorf("Expected the original error to be wrapped, got %v", err)
+				}
+				if err.Error() != tt.err.Error() {
+					t.Errorf("Expected the original message %q, got %q", tt.err, err)
+				}
+				var gremlinErr *driver.GremlinError
+				if !errors.As(err, &gremlinErr) {
+					t.Fatalf("Expected a *GremlinError, got %T", err)
+				}
+				if gremlinErr.StatusCode != tt.wantStatus {
+					t.Errorf("Expected status code %d, got %d", tt.wantStatus, gremlinErr.StatusCode)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"UnknownErrorsAreUnchanged", func(t *testing.T) {
+			t.Parallel()
+			original := errors.New("unexpected end of JSON input")
+			server := &countingServer{err: original}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); err != original { //nolint:errorlint // identity check
+				t.Errorf("Expected the original error, got %#v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"NoResultsIsNotFound", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Take(); !errors.Is(err, gsmtypes.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"DialectWithoutTransactions", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{Dialect: driver.CosmosDBDialect{}}, server.submit)
+
+			_, err := db.Begin()
+			if !errors.Is(err, driver.ErrTransactionUnsupported) {
+				t.Errorf("Expected ErrTransactionUnsupported, got %v", err)
+			}
+			if err.Error() != "transactions are not supported by cosmosdb" {
+				t.Errorf("Expected the dialect in the message, got %q", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,109 @@
+package driver
+
+import (
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// toList runs traversal and returns every result. Traversals are prepared
+// for the driver's views and submitted as bytecode unless the driver is in
+// script mode, in which case they are translated to a parameterized script
+// first. Errors are classified with classifyError and update the writer's
+// connection state.
+func (driver *GremlinDriver) toList(traversal *gremlingo.GraphTraversal) ([]*gremlingo.Result, error) {
+	traversal, err := driver.prepare(traversal)
+	if err != nil {
+		return nil, err
+	}
+	traversal, timeout := driver.applyTimeout(traversal)
+	return withDeadline(
+		timeout, func() ([]*gremlingo.Result, error) {
+			var results []*gremlingo.Result
+			var err error
+			if driver.scripts == nil {
+				results, err = traversal.ToList()
+				err = classifyError(err)
+			} else {
+				results, err = submitScript(driver.scripts, traversal, driver.dialect)
+			}
+			driver.connection.observe(err)
+			return results, err
+		},
+	)
+}
+
+// submitScript translates traversal for dialect and submits it.
+func submitScript(
+	submitter scriptSubmitter,
+	traversal *gremlingo.GraphTraversal,
+	dialect Dialect,
+) ([]*gremlingo.Result, error) {
+	script, bindings, requestArgs, err := translateScript(traversal, scriptOptionsFor(dialect))
+	if err != nil {
+		return nil, err
+	}
+	results, err := submitter.submitScript(script, bindings, requestArgs)
+	return results, classifyError(err)
+}
+
+// next runs traversal and returns its first result, failing with
+// errGremlinNotFound when there is none.
+func (driver *GremlinDriver) next(traversal *gremlingo.GraphTraversal) (*gremlingo.Result, error) {
+	if driver.scripts == nil {
+		traversal, err := driver.prepare(traversal)
+		if err != nil {
+			return nil, err
+		}
+		traversal, timeout := driver.applyTimeout(traversal)
+		return withDeadline(
+			timeout, func() (*gremlingo.Result, error) {
+				result, err := traversal.Next()
+				err = classifyError(err)
+				driver.connection.observe(err)
+				return result, err
+			},
+		)
+	}
+	results, err := driver.toList(traversal)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errGremlinNotFound
+	}
+	return results[0], nil
+}
+
+// iterate runs traversal for its side effects, discarding results.
+func (driver *GremlinDriver) iterate(traversal *gremlingo.GraphTraversal) error {
+	if driver.scripts == nil {
+		traversal, err := driver.prepare(traversal)
+		if err != nil {
+			return err
+		}
+		traversal, timeout := driver.applyTimeout(traversal)
+		_, err = withDeadline(
+			timeout, func() (struct{}, error) {
+				err := classifyError(<-traversal.Iterate())
+				driver.connection.observe(err)
+				return struct{}{}, err
+			},
+		)
+		return err
+	}
+	_, err := driver.toList(traversal)
+	return err
+}
+
+// runScript submits a Groovy script to the writer, whether or not the driver
+// is in script mode. It is used for server-side APIs that have no traversal
+// form, such as JanusGraph schema management.
+func (driver *GremlinDriver) runScript(script string) ([]*gremlingo.Result, error) {
+	submitter := driver.scripts
+	if submitter == nil {
+		submitter = remoteScriptSubmitter{remote: driver.remoteConn}
+	}
+	results, err := submitter.submitScript(script, map[string]any{}, nil)
+	err = classifyError(err)
+	driver.connection.observe(err)
+	return results, err
+}
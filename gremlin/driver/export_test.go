@@ -1,20 +1,39 @@
 package driver
 
-import "github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+	appLogger "github.com/jbrusegaard/graph-struct-manager/log"
+)
 
 // GremlinTagOptionsForTest mirrors gremlinTagOptions for external tests.
 type GremlinTagOptionsForTest struct {
-	Name      string
-	OmitEmpty bool
-	Unmapped  bool
+	Name         string
+	OmitEmpty    bool
+	Unmapped     bool
+	PartitionKey bool
+	Index        bool
+	Unique       bool
+	UniqueGroup  string
+	Mixed        bool
 }
 
 func ParseGremlinTagForTest(tag string) GremlinTagOptionsForTest {
 	opts := parseGremlinTag(tag)
 	return GremlinTagOptionsForTest{
-		Name:      opts.name,
-		OmitEmpty: opts.omitEmpty,
-		Unmapped:  opts.unmapped,
+		Name:         opts.name,
+		OmitEmpty:    opts.omitEmpty,
+		Unmapped:     opts.unmapped,
+		PartitionKey: opts.partitionKey,
+		Index:        opts.index,
+		Unique:       opts.unique,
+		UniqueGroup:  opts.uniqueGroup,
+		Mixed:        opts.mixed,
 	}
 }
 
@@ -47,3 +66,127 @@ func GetLabelFromVertexForTest(value gsmtypes.VertexType) string {
 func ValidateStructPointerWithAnonymousVertexForTest(value any) error {
 	return validateStructPointerWithAnonymousVertex(value)
 }
+
+func UnloadPolymorphicForTest[T any](v *T, mapResult map[any]any) error {
+	return unloadPolymorphic(v, mapResult)
+}
+
+func DialectForTest(config Config) Dialect {
+	return dialectFor(config)
+}
+
+// ScriptSubmitterFunc lets external tests stand in for a script-only server.
+type ScriptSubmitterFunc func(
+	script string,
+	bindings map[string]any,
+	requestArgs map[string]any,
+) ([]*gremlingo.Result, error)
+
+func (f ScriptSubmitterFunc) submitScript(
+	script string,
+	bindings map[string]any,
+	requestArgs map[string]any,
+) ([]*gremlingo.Result, error) {
+	return f(script, bindings, requestArgs)
+}
+
+// NewScriptDriverForTest returns a driver in script mode that submits every
+// traversal to submitter instead of a server, and reads to readers if given.
+func NewScriptDriverForTest(
+	config Config,
+	submitter ScriptSubmitterFunc,
+	readers ...ScriptSubmitterFunc,
+) *GremlinDriver {
+	logger := appLogger.InitializeLogger()
+	db := &GremlinDriver{
+		g: gremlingo.NewDefaultGraphTraversalSource(),
+		connection: &connectionState{
+			url:      "writer",
+			logger:   logger,
+			onChange: config.OnConnectionStateChange,
+		},
+		logger:       logger,
+		dialect:      dialectFor(config),
+		idGenerator:  config.IDGenerator,
+		queryTimeout: config.QueryTimeout,
+		guardrails:   config.Guardrails,
+		validator:    validatorFor(config),
+		scripts:      submitter,
+		tenantKey:    tenantKeyFor(config),
+		timestamps:   timestampsFor(config),
+		auditSink:    config.AuditSink,
+	}
+	if config.Retry != nil && config.Retry.MaxAttempts > 1 {
+		policy := config.Retry.withDefaults()
+		db.retryPolicy = &policy
+	}
+	if len(readers) > 0 {
+		db.readers = &readerPool{}
+		for i, readerSubmitter := range readers {
+			db.readers.readers = append(
+				db.readers.readers,
+				&reader{
+					url:     fmt.Sprintf("reader-%d", i),
+					scripts: readerSubmitter,
+					state: &connectionState{
+						url:      fmt.Sprintf("reader-%d", i),
+						logger:   logger,
+						onChange: config.OnConnectionStateChange,
+					},
+				},
+			)
+		}
+	}
+	return db
+}
+
+func TranslateScriptForTest(
+	traversal *gremlingo.GraphTraversal,
+	dialect Dialect,
+) (string, map[string]any, error) {
+	script, bindings, _, err := translateScript(traversal, scriptOptionsFor(dialect))
+	return script, bindings, err
+}
+
+// BytecodeLayoutErrorForTest returns the error of checking the gremlingo
+// bytecode layout readField reads.
+func BytecodeLayoutErrorForTest() error {
+	return errBytecodeLayout
+}
+
+// BytecodeLayoutVersionForTest is the gremlin-go version readField is
+// written against.
+const BytecodeLayoutVersionForTest = bytecodeLayoutVersion
+
+func CheckFieldsForTest(rt reflect.Type, fields map[string]reflect.Kind) error {
+	return checkFields(rt, fields)
+}
+
+func SignSigV4ForTest(
+	req *http.Request,
+	body []byte,
+	credentials AWSCredentials,
+	region string,
+	service string,
+	now time.Time,
+) {
+	signSigV4(req, body, credentials, region, service, now)
+}
+
+func IAMHandshakeHeaderForTest(endpoint string, config IAMAuthConfig, now time.Time) (http.Header, error) {
+	signer, err := newIAMSigner(endpoint, config)
+	if err != nil {
+		return nil, err
+	}
+	signer.now = func() time.Time { return now }
+	return signer.header()
+}
+
+func RetryBackoffForTest(policy RetryPolicy, attempt int) time.Duration {
+	return policy.withDefaults().backoff(attempt)
+}
+
+// PingAllForTest runs one round of the background health check.
+func PingAllForTest(db *GremlinDriver) error {
+	return db.pingAll()
+}
@@ -3,6 +3,7 @@ package driver
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // edgeDirection represents the direction of an edge traversal for a
@@ -15,6 +16,18 @@ const (
 	edgeDirectionBoth
 )
 
+// reverse returns the opposite traversal direction; both stays both.
+func (d edgeDirection) reverse() edgeDirection {
+	switch d {
+	case edgeDirectionOut:
+		return edgeDirectionIn
+	case edgeDirectionIn:
+		return edgeDirectionOut
+	case edgeDirectionBoth:
+	}
+	return edgeDirectionBoth
+}
+
 // gremlinEdgeTagOptions holds parsed gremlinEdge tag information
 type gremlinEdgeTagOptions struct {
 	label     string
@@ -60,15 +73,25 @@ func parseGremlinEdgeTag(tag string) (gremlinEdgeTagOptions, error) {
 
 // gremlinTagOptions holds parsed gremlin tag information
 type gremlinTagOptions struct {
-	name      string
-	omitEmpty bool
-	unmapped  bool
+	name         string
+	omitEmpty    bool
+	unmapped     bool
+	partitionKey bool
+	index        bool
+	unique       bool
+	uniqueGroup  string
+	mixed        bool
 }
 
 // parseGremlinTag parses a gremlin tag and returns the property name and options
 // Examples:
 //   - "field_name" -> {name: "field_name", omitEmpty: false}
 //   - "field_name,omitempty" -> {name: "field_name", omitEmpty: true}
+//   - "pk,partitionkey" -> {name: "pk", partitionKey: true}
+//   - "email,index" -> {name: "email", index: true}
+//   - "email,unique" -> {name: "email", unique: true}
+//   - "first,unique=name" -> {name: "first", unique: true, uniqueGroup: "name"}
+//   - "bio,mixed" -> {name: "bio", mixed: true}
 func parseGremlinTag(tag string) gremlinTagOptions {
 	parts := splitTag(tag)
 
@@ -86,6 +109,22 @@ func parseGremlinTag(tag string) gremlinTagOptions {
 		if parts[i] == "unmapped" {
 			opts.unmapped = true
 		}
+		if parts[i] == "partitionkey" {
+			opts.partitionKey = true
+		}
+		if parts[i] == "index" {
+			opts.index = true
+		}
+		if parts[i] == "unique" {
+			opts.unique = true
+		}
+		if group, ok := strings.CutPrefix(parts[i], "unique="); ok && group != "" {
+			opts.unique = true
+			opts.uniqueGroup = group
+		}
+		if parts[i] == "mixed" {
+			opts.mixed = true
+		}
 	}
 
 	return opts
@@ -9,11 +9,16 @@ import (
 func TestParseGremlinTag(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
-		name         string
-		tag          string
-		wantName     string
-		wantOmit     bool
-		wantUnmapped bool
+		name             string
+		tag              string
+		wantName         string
+		wantOmit         bool
+		wantUnmapped     bool
+		wantPartitionKey bool
+		wantIndex        bool
+		wantUnique       bool
+		wantUniqueGroup  string
+		wantMixed        bool
 	}{
 		{
 			name:         "NameOnly",
@@ -43,6 +48,32 @@ func TestParseGremlinTag(t *testing.T) {
 			wantOmit:     true,
 			wantUnmapped: true,
 		},
+		{
+			name:             "PartitionKey",
+			tag:              "pk,partitionkey",
+			wantName:         "pk",
+			wantPartitionKey: true,
+		},
+		{
+			name:      "Index",
+			tag:       "email,index",
+			wantName:  "email",
+			wantIndex: true,
+		},
+		{
+			name:       "UniqueAndMixed",
+			tag:        "email,unique,mixed",
+			wantName:   "email",
+			wantUnique: true,
+			wantMixed:  true,
+		},
+		{
+			name:            "UniqueGroup",
+			tag:             "first_name,unique=full_name",
+			wantName:        "first_name",
+			wantUnique:      true,
+			wantUniqueGroup: "full_name",
+		},
 	}
 
 	for _, tt := range tests {
@@ -58,6 +89,21 @@ func TestParseGremlinTag(t *testing.T) {
 			if opts.Unmapped != tt.wantUnmapped {
 				t.Errorf("unmapped should be %v, got %v", tt.wantUnmapped, opts.Unmapped)
 			}
+			if opts.PartitionKey != tt.wantPartitionKey {
+				t.Errorf("partitionKey should be %v, got %v", tt.wantPartitionKey, opts.PartitionKey)
+			}
+			if opts.Index != tt.wantIndex {
+				t.Errorf("index should be %v, got %v", tt.wantIndex, opts.Index)
+			}
+			if opts.Unique != tt.wantUnique {
+				t.Errorf("unique should be %v, got %v", tt.wantUnique, opts.Unique)
+			}
+			if opts.UniqueGroup != tt.wantUniqueGroup {
+				t.Errorf("uniqueGroup should be %q, got %q", tt.wantUniqueGroup, opts.UniqueGroup)
+			}
+			if opts.Mixed != tt.wantMixed {
+				t.Errorf("mixed should be %v, got %v", tt.wantMixed, opts.Mixed)
+			}
 		})
 	}
 }
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrUnconditionedQuery is returned by Delete and Updates when
+	// Guardrails.RequireConditions is set and the query has no condition,
+	// id or partition key.
+	ErrUnconditionedQuery = errors.New("query has no conditions")
+	// ErrTooManyRows is returned by Find when the query matches more than
+	// Guardrails.MaxRows vertices.
+	ErrTooManyRows = errors.New("query returned too many rows")
+)
+
+// Guardrails protect the graph from queries that scan or modify every vertex
+// of a label by mistake. The zero value disables them all; a query opts out
+// with AllowGlobal.
+type Guardrails struct {
+	// MaxRows fails Find with ErrTooManyRows when the query matches more
+	// than MaxRows vertices. Zero means no limit.
+	MaxRows int
+	// RequireConditions refuses Delete and Updates with
+	// ErrUnconditionedQuery when the query does not narrow its label.
+	RequireConditions bool
+	// WarnUnindexed logs a warning for queries that filter on no id,
+	// partition key or property tagged index, unique or mixed, e.g.
+	// `gremlin:"email,index"`.
+	WarnUnindexed bool
+}
+
+// AllowGlobal lifts the driver's guardrails for this query, allowing Delete
+// and Updates without conditions and Find to return more than MaxRows.
+func (q *Query[T]) AllowGlobal() *Query[T] {
+	q.writeDebugString(".AllowGlobal()")
+	q.allowGlobal = true
+	return q
+}
+
+// hasConditions reports whether the query narrows its label. A PreQuery
+// does not count: it may be any traversal, filtering or not.
+func (q *Query[T]) hasConditions() bool {
+	return len(q.conditions) > 0 || len(q.ids) > 0 || q.partitionKey != nil
+}
+
+// requireConditions returns ErrUnconditionedQuery when the guardrails require
+// operation to be narrowed and it is not.
+func (q *Query[T]) requireConditions(operation string) error {
+	if !q.db.guardrails.RequireConditions || q.allowGlobal || q.hasConditions() {
+		return nil
+	}
+	return fmt.Errorf(
+		"%s: %w; call AllowGlobal to run it on every %s vertex",
+		operation, ErrUnconditionedQuery, reflect.TypeFor[T](),
+	)
+}
+
+// maxRows returns the row limit Find enforces, or 0 when there is none.
+func (q *Query[T]) maxRows() int {
+	if q.allowGlobal {
+		return 0
+	}
+	return q.db.guardrails.MaxRows
+}
+
+// warnUnindexed logs a warning when the guardrails ask for it and the query
+// filters on nothing the graph can look up by index.
+func (q *Query[T]) warnUnindexed() {
+	if !q.db.guardrails.WarnUnindexed || q.allowGlobal {
+		return
+	}
+	if len(q.ids) > 0 || q.partitionKey != nil {
+		return
+	}
+	schema := schemaFor(reflect.TypeFor[T]())
+	for _, condition := range q.conditions {
+		if condition.field == "id" {
+			return
+		}
+		if field, ok := schema.mapFieldByTag(condition.field); ok && (field.indexed || field.mixed) {
+			return
+		}
+	}
+	q.db.logger.Warnf("Query on %s filters on no indexed property and may scan every vertex", reflect.TypeFor[T]())
+}
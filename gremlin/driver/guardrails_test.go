@@ -0,0 +1,104 @@
+package driver_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+func TestGuardrails(t *testing.T) {
+	t.Parallel()
+	topic := map[any]any{"id": "1", "label": "test_topic", "title": "graphs"}
+	guarded := driver.Config{Guardrails: driver.Guardrails{MaxRows: 2, RequireConditions: true}}
+
+	t.Run(
+		"RefusesUnconditionedWrites", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{}
+			db := driver.NewScriptDriverForTest(guarded, server.submit)
+
+			if err := driver.Model[testTopic](db).Delete(); !errors.Is(err, driver.ErrUnconditionedQuery) {
+				t.Errorf("Expected ErrUnconditionedQuery from Delete, got %v", err)
+			}
+			err := driver.Model[testTopic](db).Updates(map[string]any{"title": "graphs"})
+			if !errors.Is(err, driver.ErrUnconditionedQuery) {
+				t.Errorf("Expected ErrUnconditionedQuery from Updates, got %v", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), "AllowGlobal") {
+				t.Errorf("Expected the error to mention AllowGlobal, got %v", err)
+			}
+			err = driver.Model[testTopic](db).PreQuery(gremlingo.T__.Identity()).Limit(1).Delete()
+			if !errors.Is(err, driver.ErrUnconditionedQuery) {
+				t.Errorf("Expected ErrUnconditionedQuery from Delete after PreQuery, got %v", err)
+			}
+			if server.requests != 0 {
+				t.Errorf("Expected no requests, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"AllowsConditionedAndGlobalWrites", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{}
+			db := driver.NewScriptDriverForTest(guarded, server.submit)
+
+			if err := driver.Model[testTopic](db).Where("title", comparator.EQ, "graphs").Delete(); err != nil {
+				t.Errorf("Expected conditioned Delete to run, got %v", err)
+			}
+			if err := driver.Model[testTopic](db).IDs("1").Delete(); err != nil {
+				t.Errorf("Expected Delete by id to run, got %v", err)
+			}
+			if err := driver.Model[testTopic](db).AllowGlobal().Delete(); err != nil {
+				t.Errorf("Expected AllowGlobal Delete to run, got %v", err)
+			}
+			if server.requests != 3 {
+				t.Errorf("Expected 3 requests, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"MaxRows", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{topic, topic, topic}, {topic, topic}, {topic, topic, topic}}}
+			db := driver.NewScriptDriverForTest(guarded, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); !errors.Is(err, driver.ErrTooManyRows) {
+				t.Errorf("Expected ErrTooManyRows, got %v", err)
+			}
+			request := server.requests[0]
+			if !strings.HasSuffix(request.script, ".limit(p7)") || request.bindings["p7"] != 3 {
+				t.Errorf("Expected Find to fetch at most MaxRows+1, got %s %v", request.script, request.bindings)
+			}
+			if topics, err := driver.Model[testTopic](db).Find(); err != nil || len(topics) != 2 {
+				t.Errorf("Expected 2 topics within the limit, got %d, %v", len(topics), err)
+			}
+			if topics, err := driver.Model[testTopic](db).AllowGlobal().Find(); err != nil || len(topics) != 3 {
+				t.Errorf("Expected AllowGlobal to lift the limit, got %d, %v", len(topics), err)
+			}
+			if strings.Contains(server.requests[2].script, "limit") {
+				t.Errorf("Expected no limit with AllowGlobal, got %s", server.requests[2].script)
+			}
+		},
+	)
+
+	t.Run(
+		"DisabledByDefault", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{response: []any{topic, topic, topic}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if err := driver.Model[testTopic](db).Delete(); err != nil {
+				t.Errorf("Expected Delete to run, got %v", err)
+			}
+			if topics, err := driver.Model[testTopic](db).Find(); err != nil || len(topics) != 3 {
+				t.Errorf("Expected 3 topics, got %d, %v", len(topics), err)
+			}
+		},
+	)
+}
@@ -0,0 +1,171 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/charmbracelet/log"
+)
+
+// defaultPingTimeout bounds the pings made by Open and the health check.
+const defaultPingTimeout = 10 * time.Second
+
+// ConnectionState is whether an endpoint is reachable.
+type ConnectionState int
+
+const (
+	// ConnectionUp means the last request to the endpoint got a response.
+	ConnectionUp ConnectionState = iota
+	// ConnectionDown means the last request to the endpoint could not be
+	// delivered or timed out waiting for a connection.
+	ConnectionDown
+)
+
+func (s ConnectionState) String() string {
+	if s == ConnectionDown {
+		return "down"
+	}
+	return "up"
+}
+
+// ConnectionEvent reports an endpoint changing state.
+type ConnectionEvent struct {
+	// URL is the endpoint as given to Open or in Config.ReaderURLs.
+	URL   string
+	State ConnectionState
+	// Err is the error that took the endpoint down; nil when it came up.
+	Err error
+}
+
+// connectionState tracks whether an endpoint is up, logging transitions
+// and reporting them to the configured callback.
+type connectionState struct {
+	url      string
+	down     atomic.Bool
+	logger   *log.Logger
+	onChange func(ConnectionEvent)
+}
+
+// observe records the outcome of a request to the endpoint. Connection
+// errors take it down; responses, including server errors, bring it up.
+func (s *connectionState) observe(err error) {
+	s.set(errors.Is(err, ErrConnection), err)
+}
+
+func (s *connectionState) set(down bool, err error) {
+	if s == nil || s.down.Swap(down) == down {
+		return
+	}
+	event := ConnectionEvent{URL: s.url, State: ConnectionUp}
+	if down {
+		event.State, event.Err = ConnectionDown, err
+		s.logger.Warnf("Connection to %s is down: %v", s.url, err)
+	} else {
+		s.logger.Infof("Connection to %s is up", s.url)
+	}
+	if s.onChange != nil {
+		s.onChange(event)
+	}
+}
+
+// Ping checks that the server answers a trivial traversal. Readers are not
+// checked; reads fall back to the writer when they are down. Use it for
+// readiness probes:
+//
+//	ctx, cancel := context.WithTimeout(r.Context(), time.Second)
+//	defer cancel()
+//	if err := db.Ping(ctx); err != nil {
+//		w.WriteHeader(http.StatusServiceUnavailable)
+//	}
+//
+// Ping fails with ErrConnection when the server cannot be reached and
+// ErrTimeout when ctx expires first.
+func (driver *GremlinDriver) Ping(ctx context.Context) error {
+	_, err := runWithContext(
+		ctx, func() ([]*gremlingo.Result, error) {
+			return driver.toList(driver.g.Inject(1))
+		},
+	)
+	if errors.Is(err, ErrTimeout) {
+		driver.connection.set(true, err)
+	}
+	if err != nil {
+		return fmt.Errorf("ping: %w", err)
+	}
+	return nil
+}
+
+// pingReader pings r, marking it unhealthy when it cannot be reached and
+// healthy again once it answers.
+func (driver *GremlinDriver) pingReader(ctx context.Context, r *reader) error {
+	_, err := runWithContext(
+		ctx, func() ([]*gremlingo.Result, error) {
+			results, err := r.toList(driver.g.Inject(1), driver.dialect)
+			r.state.observe(err)
+			return results, err
+		},
+	)
+	switch {
+	case err == nil:
+		r.markHealthy()
+	case errors.Is(err, ErrConnection), errors.Is(err, ErrTimeout):
+		r.state.set(true, err)
+		r.markUnhealthy(time.Now())
+	}
+	return err
+}
+
+// pingAll pings the writer and every reader, returning their errors joined.
+func (driver *GremlinDriver) pingAll() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultPingTimeout)
+	defer cancel()
+	errs := []error{driver.Ping(ctx)}
+	if driver.readers != nil {
+		for _, r := range driver.readers.readers {
+			if err := driver.pingReader(ctx, r); err != nil {
+				errs = append(errs, fmt.Errorf("ping reader %s: %w", r.url, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// healthCheck pings the writer and readers in the background. Gremlingo
+// replaces dropped websockets with new ones on the next request, so the
+// pings also reconnect idle endpoints before a query needs them.
+type healthCheck struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (driver *GremlinDriver) startHealthCheck(interval time.Duration) {
+	check := &healthCheck{stop: make(chan struct{})}
+	check.wg.Add(1)
+	go func() {
+		defer check.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-check.stop:
+				return
+			case <-ticker.C:
+				_ = driver.pingAll()
+			}
+		}
+	}()
+	driver.healthCheck = check
+}
+
+func (check *healthCheck) close() {
+	if check == nil {
+		return
+	}
+	close(check.stop)
+	check.wg.Wait()
+}
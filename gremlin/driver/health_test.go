@@ -0,0 +1,158 @@
+package driver_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+var errConnectionRefused = errors.New("E0104: no successful connections could be made: dial tcp: connection refused")
+
+// eventRecorder collects connection state events.
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []driver.ConnectionEvent
+}
+
+func (r *eventRecorder) record(event driver.ConnectionEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *eventRecorder) states() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := make([]string, len(r.events))
+	for i, event := range r.events {
+		states[i] = event.URL + " " + event.State.String()
+	}
+	return states
+}
+
+func TestPing(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"AnswersTrivialTraversal", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{int64(1)}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if err := db.Ping(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+			if len(server.requests) != 1 || server.requests[0].script != "g.inject(p0)" {
+				t.Errorf("Expected a single g.inject(p0), got %+v", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"UnreachableServer", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{err: errConnectionRefused}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if err := db.Ping(context.Background()); !errors.Is(err, driver.ErrConnection) {
+				t.Errorf("Expected ErrConnection, got %v", err)
+			}
+		},
+	)
+
+	t.Run(
+		"ContextDeadline", func(t *testing.T) {
+			t.Parallel()
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			hanging := func(string, map[string]any, map[string]any) ([]*gremlingo.Result, error) {
+				<-release
+				return nil, nil
+			}
+			events := &eventRecorder{}
+			db := driver.NewScriptDriverForTest(driver.Config{OnConnectionStateChange: events.record}, hanging)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+			if err := db.Ping(ctx); !errors.Is(err, driver.ErrTimeout) {
+				t.Errorf("Expected ErrTimeout, got %v", err)
+			}
+			if got := events.states(); len(got) != 1 || got[0] != "writer down" {
+				t.Errorf("Expected [writer down], got %v", got)
+			}
+		},
+	)
+}
+
+func TestConnectionStateEvents(t *testing.T) {
+	t.Parallel()
+	topic := map[any]any{"id": "1", "label": "test_topic", "title": "graphs"}
+
+	t.Run(
+		"WriterGoesDownAndRecovers", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 2, err: errConnectionRefused, response: []any{topic}}
+			events := &eventRecorder{}
+			db := driver.NewScriptDriverForTest(driver.Config{OnConnectionStateChange: events.record}, server.submit)
+
+			for range 3 {
+				_, _ = driver.Model[testTopic](db).Find()
+			}
+			got := events.states()
+			if len(got) != 2 || got[0] != "writer down" || got[1] != "writer up" {
+				t.Errorf("Expected [writer down, writer up], got %v", got)
+			}
+		},
+	)
+
+	t.Run(
+		"ServerErrorsKeepTheConnectionUp", func(t *testing.T) {
+			t.Parallel()
+			server := &countingServer{err: errors.New("E0502: error message 'No such property'. statusCode: 597")}
+			events := &eventRecorder{}
+			db := driver.NewScriptDriverForTest(driver.Config{OnConnectionStateChange: events.record}, server.submit)
+
+			_, _ = driver.Model[testTopic](db).Find()
+			if got := events.states(); len(got) != 0 {
+				t.Errorf("Expected no events, got %v", got)
+			}
+		},
+	)
+
+	t.Run(
+		"HealthCheckRestoresReader", func(t *testing.T) {
+			t.Parallel()
+			writer := &countingServer{response: []any{topic}}
+			replica := &flakyServer{failures: 1, err: errConnectionRefused, response: []any{topic}}
+			events := &eventRecorder{}
+			db := driver.NewScriptDriverForTest(
+				driver.Config{OnConnectionStateChange: events.record},
+				writer.submit,
+				replica.submit,
+			)
+
+			// The failed read marks the reader unhealthy for the retry interval.
+			if _, err := driver.Model[testTopic](db).Find(); err != nil {
+				t.Fatal(err)
+			}
+			if err := driver.PingAllForTest(db); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := driver.Model[testTopic](db).Find(); err != nil {
+				t.Fatal(err)
+			}
+			if replica.requests != 3 {
+				t.Errorf("Expected the reader to serve reads after the health check, got %d requests", replica.requests)
+			}
+			got := events.states()
+			if len(got) != 2 || got[0] != "reader-0 down" || got[1] != "reader-0 up" {
+				t.Errorf("Expected [reader-0 down, reader-0 up], got %v", got)
+			}
+		},
+	)
+}
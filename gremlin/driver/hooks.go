@@ -53,6 +53,11 @@ func runAfterFindHook[T any](db *GremlinDriver, value *T) error {
 		return nil
 	}
 	hook, ok := any(value).(AfterFindHook)
+	if !ok {
+		// Interface typed results hold the concrete model, which may
+		// implement the hook itself.
+		hook, ok = any(*value).(AfterFindHook)
+	}
 	if !ok {
 		return nil
 	}
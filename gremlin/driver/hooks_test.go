@@ -92,7 +92,7 @@ func (v *hookFindErrorVertex) AfterFind(db *driver.GremlinDriver) error {
 func TestCreateHooksCreate(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -131,7 +131,7 @@ func TestCreateHooksCreate(t *testing.T) {
 func TestUpdateHooksUpdate(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -179,7 +179,7 @@ func TestUpdateHooksUpdate(t *testing.T) {
 func TestAfterFindHooks(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -237,7 +237,7 @@ func TestAfterFindHooks(t *testing.T) {
 func TestAfterFindHookError(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
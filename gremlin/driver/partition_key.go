@@ -0,0 +1,57 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+)
+
+// PartitionKey restricts the query, including ID lookups, to the partition
+// holding value. T must declare its partition key property with the
+// partitionkey tag option, e.g. `gremlin:"pk,partitionkey"`.
+func (q *Query[T]) PartitionKey(value any) *Query[T] {
+	schema := schemaFor(reflect.TypeFor[T]())
+	if schema.partitionKey == nil {
+		q.err = fmt.Errorf("PartitionKey: %s has no partitionkey field", reflect.TypeFor[T]())
+		return q
+	}
+	q.partitionKey = &QueryCondition{
+		field:    schema.partitionKey.tagName,
+		operator: comparator.EQ,
+		value:    value,
+	}
+	q.writeDebugString(q.partitionKey.String())
+	return q
+}
+
+// addPartitionKey appends the partition key filter set by PartitionKey.
+func (q *Query[T]) addPartitionKey(query *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+	if q.partitionKey == nil {
+		return query
+	}
+	return query.Has(q.partitionKey.field, q.partitionKey.value)
+}
+
+// partitionKeyOf returns the partition key property name and value of the
+// struct pointed to by value. ok is false when its type declares no
+// partition key.
+func partitionKeyOf(value any) (string, any, bool) {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return "", nil, false
+		}
+		rv = rv.Elem()
+	}
+	schema := schemaFor(rv.Type())
+	if schema.partitionKey == nil {
+		return "", nil, false
+	}
+	field := rv.FieldByIndex(schema.partitionKey.index)
+	if field.IsZero() {
+		return schema.partitionKey.tagName, nil, true
+	}
+	return schema.partitionKey.tagName, field.Interface(), true
+}
@@ -0,0 +1,296 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+// defaultPathMaxHops bounds path searches when PathOptions.MaxHops is unset.
+const defaultPathMaxHops = 5
+
+// PathDirection selects which edges a path search follows from each vertex.
+type PathDirection int
+
+const (
+	// PathBoth follows edges regardless of direction (default).
+	PathBoth PathDirection = iota
+	// PathOut follows outgoing edges.
+	PathOut
+	// PathIn follows incoming edges.
+	PathIn
+)
+
+// PathOptions configures ShortestPath and Paths.
+type PathOptions struct {
+	// EdgeLabels restricts the search to edges with these labels. All edges
+	// are followed when empty.
+	EdgeLabels []string
+	// Direction selects the edges followed from each vertex.
+	Direction PathDirection
+	// MaxHops is the maximum number of edges in a path (default 5).
+	MaxHops int
+	// WeightProperty names an edge property summed as the path weight. When
+	// empty every edge weighs 1, so the weight is the number of hops. Every
+	// traversed edge must have the property.
+	WeightProperty string
+	// Limit caps the number of paths returned by Paths. Zero returns all.
+	Limit int
+}
+
+// PathEdge is an edge connecting two consecutive hops of a Path.
+type PathEdge struct {
+	ID         any
+	Label      string
+	OutV       any
+	InV        any
+	Properties map[string]any
+}
+
+// PathHop is a vertex on a Path together with the edge that led to it.
+type PathHop[T any] struct {
+	ID     any
+	Label  string
+	Vertex T
+	// Edge is the edge from the previous hop; nil for the first hop.
+	Edge *PathEdge
+}
+
+// Path is a simple path between two vertices.
+type Path[T any] struct {
+	Hops   []PathHop[T]
+	Weight float64
+}
+
+// ShortestPath returns the lowest weight path between the vertices with ids
+// fromID and toID, where the weight is the number of hops unless
+// opts.WeightProperty is set. Each hop's vertex is unloaded into T; when T is
+// an interface type (e.g. any) the hop holds the model registered for the
+// vertex label with RegisterModel, or a map[string]any of its properties for
+// unregistered labels. gsmtypes.ErrNotFound is returned when no path exists within
+// opts.MaxHops.
+//
+// Without opts.WeightProperty the search stops at the first path reaching
+// toID, which the breadth-first repeat finds first. A weighted search must
+// enumerate every simple path of up to opts.MaxHops edges before ordering
+// them, which grows exponentially with MaxHops on dense graphs; keep MaxHops
+// small or use the provider's OLAP shortestPath() step through G() there.
+func ShortestPath[T any](db *GremlinDriver, fromID any, toID any, opts PathOptions) (*Path[T], error) {
+	opts.Limit = 1
+	paths, err := findPaths[T](db, pathTraversal(db, fromID, toID, opts, opts.WeightProperty == ""))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, gsmtypes.ErrNotFound
+	}
+	return &paths[0], nil
+}
+
+// Paths returns the simple paths between the vertices with ids fromID and
+// toID, ordered by ascending weight. See ShortestPath for how hops are
+// unloaded. Every simple path of up to opts.MaxHops edges is enumerated,
+// so the cost grows exponentially with MaxHops on dense graphs.
+func Paths[T any](db *GremlinDriver, fromID any, toID any, opts PathOptions) ([]Path[T], error) {
+	return findPaths[T](db, pathTraversal(db, fromID, toID, opts, false))
+}
+
+// findPaths runs a traversal built by pathTraversal and unloads its paths.
+func findPaths[T any](db *GremlinDriver, traversal *gremlingo.GraphTraversal) ([]Path[T], error) {
+	results, err := db.toList(traversal)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]Path[T], 0, len(results))
+	for _, result := range results {
+		path, pathErr := unloadPath[T](db, result)
+		if pathErr != nil {
+			return nil, pathErr
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// pathTraversal builds the weighted path search:
+//
+//	g.withSack(0).V(from).
+//		repeat(outE(labels).sack(sum).by(weight).inV().simplePath()).
+//		until(or(hasId(to), loops().is(gte(maxHops)))).
+//		hasId(to).order().by(sack()).
+//		project("path", "weight").by(path().by(vertexMap).by(elementMap())).by(sack())
+//
+// firstFound leaves out the order step, so that a limited search stops at
+// the first paths reaching to instead of enumerating all of them.
+func pathTraversal(
+	db *GremlinDriver,
+	fromID any,
+	toID any,
+	opts PathOptions,
+	firstFound bool,
+) *gremlingo.GraphTraversal {
+	maxHops := opts.MaxHops
+	if maxHops <= 0 {
+		maxHops = defaultPathMaxHops
+	}
+	labels := SliceToAnySlice(opts.EdgeLabels)
+
+	var step *gremlingo.GraphTraversal
+	switch opts.Direction {
+	case PathOut:
+		step = anonymousTraversal.OutE(labels...)
+	case PathIn:
+		step = anonymousTraversal.InE(labels...)
+	case PathBoth:
+		step = anonymousTraversal.BothE(labels...)
+	}
+	if opts.WeightProperty != "" {
+		step = step.Sack(gremlingo.Operator.Sum).By(opts.WeightProperty)
+	} else {
+		step = step.Sack(gremlingo.Operator.Sum).By(anonymousTraversal.Constant(1))
+	}
+	switch opts.Direction {
+	case PathOut:
+		step = step.InV()
+	case PathIn:
+		step = step.OutV()
+	case PathBoth:
+		step = step.OtherV()
+	}
+
+	traversal := db.g.WithSack(0).V(fromID).
+		Repeat(step.SimplePath()).
+		Until(anonymousTraversal.Or(
+			anonymousTraversal.HasId(toID),
+			anonymousTraversal.Loops().Is(P.Gte(maxHops)),
+		)).
+		HasId(toID)
+	if !firstFound {
+		traversal = traversal.Order().By(anonymousTraversal.Sack(), Order.Asc)
+	}
+	if opts.Limit > 0 {
+		traversal = traversal.Limit(opts.Limit)
+	}
+	// Paths alternate vertex, edge, vertex, so the path by-modulators apply
+	// round-robin to vertices and edges respectively.
+	return traversal.Project("path", "weight").
+		By(
+			anonymousTraversal.Path().
+				By(anonymousTraversal.ValueMap(true).By(unfoldSingleValueTraversal())).
+				By(anonymousTraversal.ElementMap()),
+		).
+		By(anonymousTraversal.Sack())
+}
+
+// unloadPath converts a projected path result into a typed Path.
+func unloadPath[T any](db *GremlinDriver, result *gremlingo.Result) (Path[T], error) {
+	var path Path[T]
+	projection, ok := result.GetInterface().(map[any]any)
+	if !ok {
+		return path, errors.New("path result is not a map")
+	}
+	gremlinPath, ok := projection["path"].(*gremlingo.Path)
+	if !ok {
+		return path, errors.New("path result does not contain a path")
+	}
+	weight, err := toFloat64(projection["weight"])
+	if err != nil {
+		return path, fmt.Errorf("path weight: %w", err)
+	}
+	path.Weight = weight
+
+	var edge *PathEdge
+	for i, object := range gremlinPath.Objects {
+		elementMap, elementOk := object.(map[any]any)
+		if !elementOk {
+			return path, fmt.Errorf("path element %d is not a map", i)
+		}
+		if i%2 == 1 {
+			edge = pathEdgeFromMap(elementMap)
+			continue
+		}
+		hop, hopErr := pathHopFromMap[T](db, elementMap)
+		if hopErr != nil {
+			return path, hopErr
+		}
+		hop.Edge = edge
+		path.Hops = append(path.Hops, hop)
+	}
+	return path, nil
+}
+
+// pathHopFromMap unloads a vertex value map into a PathHop.
+func pathHopFromMap[T any](db *GremlinDriver, vertexMap map[any]any) (PathHop[T], error) {
+	hop := PathHop[T]{ID: vertexMap["id"]}
+	hop.Label, _ = vertexMap["label"].(string)
+	canonical, ok := db.timestamps.canonical(vertexMap).(map[any]any)
+	if !ok {
+		return hop, fmt.Errorf("path vertex %v is not a map", hop.ID)
+	}
+	vertex, err := unloadVertexMap[T](canonical)
+	if err != nil {
+		return hop, err
+	}
+	if err = runAfterFindHook(db, &vertex); err != nil {
+		return hop, err
+	}
+	hop.Vertex = vertex
+	return hop, nil
+}
+
+// unloadVertexMap unloads a vertex value map into T. Interface types are
+// resolved through the model registry, falling back to a map[string]any of
+// the properties.
+func unloadVertexMap[T any](vertexMap map[any]any) (T, error) {
+	var v T
+	if reflect.TypeFor[T]().Kind() == reflect.Interface {
+		err := unloadPolymorphic(&v, vertexMap)
+		return v, err
+	}
+	if err := unloadGremlinMapIntoStruct(&v, vertexMap); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// pathEdgeFromMap converts an edge elementMap into a PathEdge.
+func pathEdgeFromMap(elementMap map[any]any) *PathEdge {
+	edge := &PathEdge{Properties: make(map[string]any, len(elementMap))}
+	for key, value := range elementMap {
+		keyStr, _ := key.(string)
+		switch keyStr {
+		case "id":
+			edge.ID = value
+		case "label":
+			edge.Label, _ = value.(string)
+		case "OUT":
+			edge.OutV = elementMapID(value)
+		case "IN":
+			edge.InV = elementMapID(value)
+		default:
+			edge.Properties[keyStr] = value
+		}
+	}
+	return edge
+}
+
+// elementMapID extracts the id from the nested vertex reference elementMap
+// returns for an edge's IN and OUT entries.
+func elementMapID(value any) any {
+	if reference, ok := value.(map[any]any); ok {
+		return reference["id"]
+	}
+	return value
+}
+
+// toFloat64 converts a numeric gremlin result into a float64.
+func toFloat64(value any) (float64, error) {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || !rv.CanConvert(reflect.TypeFor[float64]()) {
+		return 0, fmt.Errorf("%v is not a number", value)
+	}
+	return rv.Convert(reflect.TypeFor[float64]()).Float(), nil
+}
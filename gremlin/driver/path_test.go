@@ -0,0 +1,194 @@
+package driver_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testCity struct {
+	gsmtypes.Vertex
+	Name string `json:"name" gremlin:"name"`
+}
+
+func addWeightedEdge(t *testing.T, db *driver.GremlinDriver, fromID any, toID any, distance int) {
+	t.Helper()
+	err := <-db.G().V(fromID).AddE("road").To(gremlingo.T__.V(toID)).
+		Property("distance", distance).Iterate()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seedCities creates a -> b -> c with distances 1 and 1, and a -> c with
+// distance 5.
+func seedCities(t *testing.T, db *driver.GremlinDriver) map[string]testCity {
+	t.Helper()
+	cities := make(map[string]testCity)
+	for _, name := range []string{"a", "b", "c", "island"} {
+		city := testCity{Name: name}
+		if err := driver.Create(db, &city); err != nil {
+			t.Fatal(err)
+		}
+		cities[name] = city
+	}
+	addWeightedEdge(t, db, cities["a"].ID, cities["b"].ID, 1)
+	addWeightedEdge(t, db, cities["b"].ID, cities["c"].ID, 1)
+	addWeightedEdge(t, db, cities["a"].ID, cities["c"].ID, 5)
+	return cities
+}
+
+func hopNames(path *driver.Path[testCity]) []string {
+	names := make([]string, 0, len(path.Hops))
+	for _, hop := range path.Hops {
+		names = append(names, hop.Vertex.Name)
+	}
+	return names
+}
+
+func TestPaths(t *testing.T) {
+	db, err := driver.Open(
+		DbURL, driver.Config{
+			Dialect: dbDialect,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run(
+		"TestShortestPathByHops", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			cities := seedCities(t, db)
+
+			path, err := driver.ShortestPath[testCity](
+				db, cities["a"].ID, cities["c"].ID, driver.PathOptions{
+					EdgeLabels: []string{"road"},
+					Direction:  driver.PathOut,
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if names := hopNames(path); len(names) != 2 || names[0] != "a" || names[1] != "c" {
+				t.Fatalf("Expected direct path a -> c, got %v", names)
+			}
+			if path.Weight != 1 {
+				t.Errorf("Expected weight 1, got %v", path.Weight)
+			}
+			if path.Hops[0].Edge != nil {
+				t.Error("Expected first hop to have no edge")
+			}
+			edge := path.Hops[1].Edge
+			if edge == nil || edge.Label != "road" || edge.Properties["distance"] == nil {
+				t.Fatalf("Expected road edge with distance, got %+v", edge)
+			}
+			if edge.OutV != cities["a"].ID || edge.InV != cities["c"].ID {
+				t.Errorf("Expected edge a -> c, got %v -> %v", edge.OutV, edge.InV)
+			}
+			if path.Hops[1].Label != "test_city" {
+				t.Errorf("Expected hop label test_city, got %s", path.Hops[1].Label)
+			}
+		},
+	)
+
+	t.Run(
+		"TestShortestPathByWeight", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			cities := seedCities(t, db)
+
+			path, err := driver.ShortestPath[testCity](
+				db, cities["a"].ID, cities["c"].ID, driver.PathOptions{
+					Direction:      driver.PathOut,
+					WeightProperty: "distance",
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := hopNames(path)
+			if len(names) != 3 || names[1] != "b" {
+				t.Fatalf("Expected path a -> b -> c, got %v", names)
+			}
+			if path.Weight != 2 {
+				t.Errorf("Expected weight 2, got %v", path.Weight)
+			}
+		},
+	)
+
+	t.Run(
+		"TestPathsOrderedAndUntyped", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			cities := seedCities(t, db)
+
+			paths, err := driver.Paths[any](
+				db, cities["c"].ID, cities["a"].ID, driver.PathOptions{
+					Direction: driver.PathIn,
+				},
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(paths) != 2 {
+				t.Fatalf("Expected 2 paths, got %d", len(paths))
+			}
+			if len(paths[0].Hops) != 2 || len(paths[1].Hops) != 3 {
+				t.Errorf("Expected paths ordered by hops, got %d and %d", len(paths[0].Hops), len(paths[1].Hops))
+			}
+			properties, ok := paths[0].Hops[0].Vertex.(map[string]any)
+			if !ok || properties["name"] != "c" {
+				t.Errorf("Expected untyped hop properties, got %+v", paths[0].Hops[0].Vertex)
+			}
+		},
+	)
+
+	t.Run(
+		"TestShortestPathNotFound", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			cities := seedCities(t, db)
+
+			_, err := driver.ShortestPath[testCity](
+				db, cities["a"].ID, cities["island"].ID, driver.PathOptions{},
+			)
+			if !errors.Is(err, gsmtypes.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got %v", err)
+			}
+		},
+	)
+}
+
+func TestShortestPathStopsAtFirstPath(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		opts      driver.PathOptions
+		wantOrder bool
+	}{
+		{name: "ByHops", opts: driver.PathOptions{EdgeLabels: []string{"road"}}},
+		{name: "ByWeight", opts: driver.PathOptions{WeightProperty: "distance"}, wantOrder: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{}}}
+				db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+				_, err := driver.ShortestPath[testCity](db, "a", "c", tt.opts)
+				if !errors.Is(err, gsmtypes.ErrNotFound) {
+					t.Fatalf("Expected ErrNotFound, got %v", err)
+				}
+				script := server.requests[0].resolvedScript()
+				if !tt.wantOrder && !strings.Contains(script, `.hasId("c").limit(1)`) {
+					t.Errorf("Expected the search to stop at the first path, got %s", script)
+				}
+				if strings.Contains(script, ".order()") != tt.wantOrder {
+					t.Errorf("Expected order step %t, got %s", tt.wantOrder, script)
+				}
+			},
+		)
+	}
+}
@@ -11,13 +11,53 @@ import (
 )
 
 // preloadNode is a node in the tree of preload paths. Each key in children is
-// a Go struct field name on the related type of the parent node.
+// a Go struct field name on the related type of the parent node. aggregates
+// is keyed by the Go field name on the related type that receives the value.
+// simplePath is set on the levels of a recursive preload so a vertex already
+// on the traversed path is not loaded again.
 type preloadNode struct {
-	children map[string]*preloadNode
+	children   map[string]*preloadNode
+	aggregates map[string]*preloadAggregate
+	simplePath bool
 }
 
 func newPreloadNode() *preloadNode {
-	return &preloadNode{children: make(map[string]*preloadNode)}
+	return &preloadNode{
+		children:   make(map[string]*preloadNode),
+		aggregates: make(map[string]*preloadAggregate),
+	}
+}
+
+// aggregateFunc is the reducing step applied to related vertices by
+// PreloadCount, PreloadSum, PreloadMin and PreloadMax.
+type aggregateFunc int
+
+const (
+	aggregateCount aggregateFunc = iota
+	aggregateSum
+	aggregateMin
+	aggregateMax
+)
+
+func (fn aggregateFunc) String() string {
+	switch fn {
+	case aggregateSum:
+		return "PreloadSum"
+	case aggregateMin:
+		return "PreloadMin"
+	case aggregateMax:
+		return "PreloadMax"
+	case aggregateCount:
+	}
+	return "PreloadCount"
+}
+
+// preloadAggregate describes an aggregate over the vertices related through
+// edgeField. property is unused for counts.
+type preloadAggregate struct {
+	edgeField string
+	property  string
+	fn        aggregateFunc
 }
 
 // Preload eagerly loads related GSM vertex structs over edges declared with
@@ -48,66 +88,307 @@ func newPreloadNode() *preloadNode {
 // For non-slice fields the first related vertex is loaded. Invalid preloads
 // surface as errors from Find/Take/ID.
 func (q *Query[T]) Preload(fieldPaths ...string) *Query[T] {
-	modelType := reflect.TypeFor[T]()
-	if modelType.Kind() == reflect.Pointer {
-		modelType = modelType.Elem()
-	}
 	for _, fieldPath := range fieldPaths {
-		rootField, err := q.mergePreloadPath(fieldPath)
+		rootField, _, err := q.mergePreloadPath(fieldPath)
 		if err != nil {
 			q.err = err
 			return q
 		}
-		traversal, err := buildPreloadTraversal(modelType, rootField, q.preloads[rootField])
-		if err != nil {
+		if err = q.rebuildPreload(rootField); err != nil {
 			q.err = err
 			return q
 		}
 		q.writeDebugString(".Preload(")
 		q.writeDebugString(fieldPath)
 		q.writeDebugString(")")
-		q.subTraversals[rootField] = traversal
 	}
 	return q
 }
 
+// PreloadCount counts the vertices related over the gremlinEdge tagged field
+// at fieldPath and writes the count into targetField, a Go field name on the
+// struct that owns the edge field. The target field must carry a
+// gremlinSubTraversal tag; its alias is used to project the value:
+//
+//	type Topic struct {
+//		gsmtypes.Vertex
+//		Title     string `gremlin:"title"`
+//		Posts     []Post `gremlinEdge:"contains"`
+//		PostCount int    `gremlinSubTraversal:"post_count"`
+//	}
+//
+//	topics, err := driver.Model[Topic](db).PreloadCount("Posts", "PostCount").Find()
+//
+// Dot separated paths aggregate at nested levels; the intermediate levels are
+// preloaded implicitly, so "Topics.Posts" loads each person's topics and sets
+// PostCount on every loaded topic.
+func (q *Query[T]) PreloadCount(fieldPath string, targetField string) *Query[T] {
+	return q.preloadAggregate(fieldPath, targetField, &preloadAggregate{fn: aggregateCount})
+}
+
+// PreloadSum sums property over the vertices related over the gremlinEdge
+// tagged field at fieldPath and writes the result into targetField. See
+// PreloadCount for the target field requirements. The sum is 0 when there are
+// no related vertices.
+func (q *Query[T]) PreloadSum(fieldPath string, property string, targetField string) *Query[T] {
+	return q.preloadAggregate(
+		fieldPath, targetField, &preloadAggregate{fn: aggregateSum, property: property},
+	)
+}
+
+// PreloadMin writes the minimum of property over the related vertices at
+// fieldPath into targetField. See PreloadCount for the target field
+// requirements. The value is 0 when there are no related vertices.
+func (q *Query[T]) PreloadMin(fieldPath string, property string, targetField string) *Query[T] {
+	return q.preloadAggregate(
+		fieldPath, targetField, &preloadAggregate{fn: aggregateMin, property: property},
+	)
+}
+
+// PreloadMax writes the maximum of property over the related vertices at
+// fieldPath into targetField. See PreloadCount for the target field
+// requirements. The value is 0 when there are no related vertices.
+func (q *Query[T]) PreloadMax(fieldPath string, property string, targetField string) *Query[T] {
+	return q.preloadAggregate(
+		fieldPath, targetField, &preloadAggregate{fn: aggregateMax, property: property},
+	)
+}
+
+// preloadAggregate registers an aggregate over the last edge field of
+// fieldPath. Root level aggregates become plain subtraversals on the query;
+// nested ones are attached to the preload node of their parent path.
+func (q *Query[T]) preloadAggregate(
+	fieldPath string,
+	targetField string,
+	aggregate *preloadAggregate,
+) *Query[T] {
+	if q.err != nil {
+		return q
+	}
+	parts := strings.Split(fieldPath, ".")
+	if slices.Contains(parts, "") || targetField == "" {
+		q.err = fmt.Errorf("%s: invalid path %q", aggregate.fn, fieldPath)
+		return q
+	}
+	aggregate.edgeField = parts[len(parts)-1]
+	q.writeDebugString(fmt.Sprintf(".%s(%s, %s)", aggregate.fn, fieldPath, targetField))
+
+	if len(parts) == 1 {
+		modelType := reflect.TypeFor[T]()
+		if modelType.Kind() == reflect.Pointer {
+			modelType = modelType.Elem()
+		}
+		alias, traversal, err := buildAggregateTraversal(modelType, targetField, aggregate, q.unscoped)
+		if err != nil {
+			q.err = err
+			return q
+		}
+		q.subTraversals[alias] = traversal
+		if q.rootAggregates == nil {
+			q.rootAggregates = make(map[string]*preloadAggregate)
+		}
+		q.rootAggregates[targetField] = aggregate
+		return q
+	}
+
+	rootField, node, err := q.mergePreloadPath(strings.Join(parts[:len(parts)-1], "."))
+	if err != nil {
+		q.err = err
+		return q
+	}
+	node.aggregates[targetField] = aggregate
+	if err = q.rebuildPreload(rootField); err != nil {
+		q.err = err
+	}
+	return q
+}
+
+// rebuildPreload (re)builds the subtraversal for a root preload field from
+// the query's preload tree.
+func (q *Query[T]) rebuildPreload(rootField string) error {
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	traversal, err := buildPreloadTraversal(
+		modelType, rootField, q.preloads[rootField], q.unscoped, q.timestamps(),
+	)
+	if err != nil {
+		return err
+	}
+	q.subTraversals[rootField] = traversal
+	return nil
+}
+
+// rebuildPreloads rebuilds every preload and root level aggregate, after a
+// change to the query's scoping.
+func (q *Query[T]) rebuildPreloads() error {
+	for rootField := range q.preloads {
+		if err := q.rebuildPreload(rootField); err != nil {
+			return err
+		}
+	}
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	for targetField, aggregate := range q.rootAggregates {
+		alias, traversal, err := buildAggregateTraversal(modelType, targetField, aggregate, q.unscoped)
+		if err != nil {
+			return err
+		}
+		q.subTraversals[alias] = traversal
+	}
+	return nil
+}
+
 // mergePreloadPath merges a dot separated preload path into the query's
-// preload tree and returns the root field name.
-func (q *Query[T]) mergePreloadPath(fieldPath string) (string, error) {
+// preload tree and returns the root field name and the node for the last
+// path segment.
+func (q *Query[T]) mergePreloadPath(fieldPath string) (string, *preloadNode, error) {
 	parts := strings.Split(fieldPath, ".")
 	if slices.Contains(parts, "") {
-		return "", fmt.Errorf("preload: invalid path %q", fieldPath)
+		return "", nil, fmt.Errorf("preload: invalid path %q", fieldPath)
 	}
 	if q.preloads == nil {
 		q.preloads = make(map[string]*preloadNode)
 	}
 	children := q.preloads
+	var node *preloadNode
 	for _, part := range parts {
-		node, ok := children[part]
+		var ok bool
+		node, ok = children[part]
 		if !ok {
 			node = newPreloadNode()
 			children[part] = node
 		}
 		children = node.children
 	}
-	return parts[0], nil
+	return parts[0], node, nil
 }
 
 // buildPreloadTraversal builds the subtraversal that fetches the related
 // vertices for a gremlinEdge tagged field as a folded list of value maps.
-// When node has children, each related vertex's value map is merged with the
-// nested preload projections so relationships load recursively.
+// When node has children or aggregates, each related vertex's value map is
+// merged with the nested projections so relationships load recursively.
+// Soft-deleted related vertices are skipped unless unscoped is set, and
+// timestamps are selected under their configured property names.
 func buildPreloadTraversal(
 	modelType reflect.Type,
 	fieldName string,
 	node *preloadNode,
+	unscoped bool,
+	timestamps timestamps,
 ) (*gremlingo.GraphTraversal, error) {
+	traversal, relatedType, err := edgeFieldTraversal(modelType, fieldName, unscoped)
+	if err != nil {
+		return nil, err
+	}
+	if node != nil && node.simplePath {
+		traversal = traversal.SimplePath()
+	}
+
+	relatedSchema := schemaFor(relatedType)
+	valueMapArgs := timestamps.selectFields(relatedSchema.selectedFields)
+	if valueMapArgs == nil {
+		valueMapArgs = []any{true}
+	}
+
+	if node == nil || (len(node.children) == 0 && len(node.aggregates) == 0) {
+		return traversal.ValueMap(valueMapArgs...).By(unfoldSingleValueTraversal()).Fold(), nil
+	}
+
+	childTraversals := make(
+		map[string]*gremlingo.GraphTraversal,
+		len(node.children)+len(node.aggregates),
+	)
+	for childName, childNode := range node.children {
+		childTraversal, childErr := buildPreloadTraversal(
+			relatedType, childName, childNode, unscoped, timestamps,
+		)
+		if childErr != nil {
+			return nil, fmt.Errorf("preload: %s: %w", fieldName, childErr)
+		}
+		childTraversals[childName] = childTraversal
+	}
+	for targetField, aggregate := range node.aggregates {
+		alias, aggregateTraversal, aggregateErr := buildAggregateTraversal(
+			relatedType, targetField, aggregate, unscoped,
+		)
+		if aggregateErr != nil {
+			return nil, fmt.Errorf("preload: %s: %w", fieldName, aggregateErr)
+		}
+		childTraversals[alias] = aggregateTraversal
+	}
+	return traversal.Local(mergedValueMapTraversal(childTraversals, valueMapArgs...)).Fold(), nil
+}
+
+// buildAggregateTraversal builds the subtraversal reducing the vertices
+// related over aggregate.edgeField on modelType, and returns it keyed by the
+// gremlinSubTraversal alias of targetField.
+func buildAggregateTraversal(
+	modelType reflect.Type,
+	targetField string,
+	aggregate *preloadAggregate,
+	unscoped bool,
+) (string, *gremlingo.GraphTraversal, error) {
+	field, ok := modelType.FieldByName(targetField)
+	if !ok {
+		return "", nil, fmt.Errorf(
+			"%s: field %s not found on struct %s",
+			aggregate.fn,
+			targetField,
+			modelType.Name(),
+		)
+	}
+	alias := field.Tag.Get(gsmtypes.GremlinSubTraversalTag)
+	if alias == "" || alias == "-" {
+		return "", nil, fmt.Errorf(
+			"%s: field %s on struct %s is missing the %s tag",
+			aggregate.fn,
+			targetField,
+			modelType.Name(),
+			gsmtypes.GremlinSubTraversalTag,
+		)
+	}
+	traversal, _, err := edgeFieldTraversal(modelType, aggregate.edgeField, unscoped)
+	if err != nil {
+		return "", nil, err
+	}
+	if aggregate.fn != aggregateCount && aggregate.property == "" {
+		return "", nil, fmt.Errorf("%s: property must not be empty", aggregate.fn)
+	}
+
+	switch aggregate.fn {
+	case aggregateSum:
+		traversal = traversal.Values(aggregate.property).Sum()
+	case aggregateMin:
+		traversal = traversal.Values(aggregate.property).Min()
+	case aggregateMax:
+		traversal = traversal.Values(aggregate.property).Max()
+	case aggregateCount:
+		return alias, traversal.Count(), nil
+	}
+	// Reducing an empty stream yields no value, which would drop the
+	// projection, so fall back to zero.
+	return alias, anonymousTraversal.Coalesce(traversal, anonymousTraversal.Constant(0)), nil
+}
+
+// edgeFieldTraversal returns the anonymous traversal from a vertex of
+// modelType to the related vertices of the gremlinEdge tagged field, filtered
+// by the related struct's label and, unless unscoped, its soft delete
+// filter and default scope, together with the related struct type.
+func edgeFieldTraversal(
+	modelType reflect.Type,
+	fieldName string,
+	unscoped bool,
+) (*gremlingo.GraphTraversal, reflect.Type, error) {
 	if modelType.Kind() != reflect.Struct {
-		return nil, fmt.Errorf("preload: type %s is not a struct", modelType.Name())
+		return nil, nil, fmt.Errorf("preload: type %s is not a struct", modelType.Name())
 	}
 	field, ok := modelType.FieldByName(fieldName)
 	if !ok {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"preload: field %s not found on struct %s",
 			fieldName,
 			modelType.Name(),
@@ -115,7 +396,7 @@ func buildPreloadTraversal(
 	}
 	edgeTag := field.Tag.Get(gsmtypes.GremlinEdgeTag)
 	if edgeTag == "" {
-		return nil, fmt.Errorf(
+		return nil, nil, fmt.Errorf(
 			"preload: field %s on struct %s is missing the %s tag",
 			fieldName,
 			modelType.Name(),
@@ -124,11 +405,11 @@ func buildPreloadTraversal(
 	}
 	tagOpts, err := parseGremlinEdgeTag(edgeTag)
 	if err != nil {
-		return nil, fmt.Errorf("preload: field %s: %w", fieldName, err)
+		return nil, nil, fmt.Errorf("preload: field %s: %w", fieldName, err)
 	}
 	relatedType, err := edgeFieldStructType(field.Type)
 	if err != nil {
-		return nil, fmt.Errorf("preload: field %s: %w", fieldName, err)
+		return nil, nil, fmt.Errorf("preload: field %s: %w", fieldName, err)
 	}
 
 	var traversal *gremlingo.GraphTraversal
@@ -143,29 +424,10 @@ func buildPreloadTraversal(
 		traversal = anonymousTraversal.Out(tagOpts.label)
 	}
 
-	relatedSchema := schemaFor(relatedType)
-	if relatedSchema.zeroLabel != "" {
-		traversal = traversal.HasLabel(relatedSchema.zeroLabel)
+	if label := schemaFor(relatedType).zeroLabel; label != "" {
+		traversal = traversal.HasLabel(label)
 	}
-
-	valueMapArgs := relatedSchema.selectedFields
-	if valueMapArgs == nil {
-		valueMapArgs = []any{true}
-	}
-
-	if node == nil || len(node.children) == 0 {
-		return traversal.ValueMap(valueMapArgs...).By(unfoldSingleValueTraversal()).Fold(), nil
-	}
-
-	childTraversals := make(map[string]*gremlingo.GraphTraversal, len(node.children))
-	for childName, childNode := range node.children {
-		childTraversal, childErr := buildPreloadTraversal(relatedType, childName, childNode)
-		if childErr != nil {
-			return nil, fmt.Errorf("preload: %s: %w", fieldName, childErr)
-		}
-		childTraversals[childName] = childTraversal
-	}
-	return traversal.Local(mergedValueMapTraversal(childTraversals, valueMapArgs...)).Fold(), nil
+	return scopeRelated(traversal, relatedType, unscoped), relatedType, nil
 }
 
 // edgeFieldStructType resolves the underlying struct type of a gremlinEdge
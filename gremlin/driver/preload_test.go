@@ -58,6 +58,41 @@ func (p *testPersonNested) Label() string {
 	return "test_person"
 }
 
+type testRatedPost struct {
+	gsmtypes.Vertex
+	Title string `json:"title" gremlin:"title"`
+	Score int    `json:"score" gremlin:"score"`
+}
+
+func (p *testRatedPost) Label() string {
+	return "test_post"
+}
+
+type testTopicWithPostStats struct {
+	gsmtypes.Vertex
+	Title      string          `json:"title"      gremlin:"title"`
+	Posts      []testRatedPost `json:"posts"                            gremlinEdge:"contains"`
+	PostCount  int             `json:"postCount"  gremlinSubTraversal:"post_count"`
+	TotalScore int             `json:"totalScore" gremlinSubTraversal:"total_score"`
+	MinScore   int             `json:"minScore"   gremlinSubTraversal:"min_score"`
+	MaxScore   int             `json:"maxScore"   gremlinSubTraversal:"max_score"`
+}
+
+func (t *testTopicWithPostStats) Label() string {
+	return "test_topic"
+}
+
+type testPersonWithTopicStats struct {
+	gsmtypes.Vertex
+	Name       string                   `json:"name"       gremlin:"name"`
+	Topics     []testTopicWithPostStats `json:"topics"                              gremlinEdge:"subscribed"`
+	TopicCount int                      `json:"topicCount" gremlinSubTraversal:"topic_count"`
+}
+
+func (p *testPersonWithTopicStats) Label() string {
+	return "test_person"
+}
+
 func addEdge(t *testing.T, db *driver.GremlinDriver, fromID any, label string, toID any) {
 	t.Helper()
 	err := <-db.G().V(fromID).AddE(label).To(gremlingo.T__.V(toID)).Iterate()
@@ -85,7 +120,7 @@ func seedPreloadData(t *testing.T, db *driver.GremlinDriver) (testPerson, []test
 func TestPreload(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -437,6 +472,99 @@ func TestPreload(t *testing.T) {
 		},
 	)
 
+	t.Run(
+		"TestPreloadAggregates", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			graphs := testTopicWithPostStats{Title: "graphs"}
+			golang := testTopicWithPostStats{Title: "golang"}
+			for _, topic := range []*testTopicWithPostStats{&graphs, &golang} {
+				if err := driver.Create(db, topic); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, score := range []int{3, 5, 10} {
+				post := testRatedPost{Title: "post", Score: score}
+				if err := driver.Create(db, &post); err != nil {
+					t.Fatal(err)
+				}
+				addEdge(t, db, graphs.ID, "contains", post.ID)
+			}
+
+			results, err := driver.Model[testTopicWithPostStats](db).
+				PreloadCount("Posts", "PostCount").
+				PreloadSum("Posts", "score", "TotalScore").
+				PreloadMin("Posts", "score", "MinScore").
+				PreloadMax("Posts", "score", "MaxScore").
+				Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 2 {
+				t.Fatalf("Expected 2 topics, got %d", len(results))
+			}
+			for _, topic := range results {
+				if len(topic.Posts) != 0 {
+					t.Errorf("Expected posts not to be loaded, got %d", len(topic.Posts))
+				}
+				switch topic.Title {
+				case "graphs":
+					if topic.PostCount != 3 || topic.TotalScore != 18 ||
+						topic.MinScore != 3 || topic.MaxScore != 10 {
+						t.Errorf("Unexpected aggregates for graphs topic: %+v", topic)
+					}
+				case "golang":
+					if topic.PostCount != 0 || topic.TotalScore != 0 ||
+						topic.MinScore != 0 || topic.MaxScore != 0 {
+						t.Errorf("Expected zero aggregates for golang topic, got %+v", topic)
+					}
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"TestPreloadCountNested", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			person := testPersonWithTopicStats{Name: "alice"}
+			if err := driver.Create(db, &person); err != nil {
+				t.Fatal(err)
+			}
+			topic := testTopicWithPostStats{Title: "graphs"}
+			if err := driver.Create(db, &topic); err != nil {
+				t.Fatal(err)
+			}
+			addEdge(t, db, person.ID, "subscribed", topic.ID)
+			for range 2 {
+				post := testRatedPost{Title: "post", Score: 1}
+				if err := driver.Create(db, &post); err != nil {
+					t.Fatal(err)
+				}
+				addEdge(t, db, topic.ID, "contains", post.ID)
+			}
+
+			result, err := driver.Model[testPersonWithTopicStats](db).
+				PreloadCount("Topics", "TopicCount").
+				PreloadCount("Topics.Posts", "PostCount").
+				Preload("Topics.Posts").
+				Take()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.TopicCount != 1 {
+				t.Errorf("Expected topic count 1, got %d", result.TopicCount)
+			}
+			if len(result.Topics) != 1 {
+				t.Fatalf("Expected 1 topic to be preloaded, got %d", len(result.Topics))
+			}
+			if result.Topics[0].PostCount != 2 {
+				t.Errorf("Expected nested post count 2, got %d", result.Topics[0].PostCount)
+			}
+			if len(result.Topics[0].Posts) != 2 {
+				t.Errorf("Expected 2 nested posts, got %d", len(result.Topics[0].Posts))
+			}
+		},
+	)
+
 	t.Run(
 		"TestPreloadNestedInvalidPathErrors", func(t *testing.T) {
 			t.Cleanup(cleanDB)
@@ -505,6 +633,81 @@ func TestPreload(t *testing.T) {
 	)
 }
 
+func TestPreloadAggregateErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		find func() error
+	}{
+		{
+			name: "unknown edge field",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadCount("NotAField", "PostCount").Find()
+				return err
+			},
+		},
+		{
+			name: "edge field without gremlinEdge tag",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadCount("Title", "PostCount").Find()
+				return err
+			},
+		},
+		{
+			name: "unknown target field",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadCount("Posts", "NotAField").Find()
+				return err
+			},
+		},
+		{
+			name: "target field without gremlinSubTraversal tag",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadCount("Posts", "Title").Find()
+				return err
+			},
+		},
+		{
+			name: "missing property",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadSum("Posts", "", "TotalScore").Find()
+				return err
+			},
+		},
+		{
+			name: "malformed path",
+			find: func() error {
+				_, err := driver.Model[testPersonWithTopicStats](nil).PreloadCount("Topics.", "PostCount").Find()
+				return err
+			},
+		},
+		{
+			name: "unknown nested target field",
+			find: func() error {
+				_, err := driver.Model[testPersonWithTopicStats](nil).PreloadCount("Topics.Posts", "TopicCount").Find()
+				return err
+			},
+		},
+		{
+			name: "count",
+			find: func() error {
+				_, err := driver.Model[testTopicWithPostStats](nil).PreloadCount("NotAField", "PostCount").Count()
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				if err := tt.find(); err == nil {
+					t.Error("Expected error, got nil")
+				}
+			},
+		)
+	}
+}
+
 func TestParseGremlinEdgeTag(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -19,7 +19,7 @@ import (
 var errGremlinNotFound = errors.New("E0903: there are no results left")
 
 func isGremlinNotFoundErr(err error) bool {
-	return err.Error() == errGremlinNotFound.Error()
+	return errors.Is(err, errGremlinNotFound)
 }
 
 type RangeCondition struct {
@@ -31,6 +31,7 @@ var cardinality = gremlingo.Cardinality
 
 // Query represents a chainable query builder
 type Query[T any] struct {
+	allowGlobal    bool
 	conditions     []*QueryCondition
 	db             *GremlinDriver
 	debug          bool
@@ -41,12 +42,17 @@ type Query[T any] struct {
 	labels         []any
 	limit          *int
 	offset         *int
+	onlyDeleted    bool
 	orderBy        *OrderCondition
+	partitionKey   *QueryCondition
 	preloads       map[string]*preloadNode
 	preTraversal   *gremlingo.GraphTraversal
 	rangeCondition *RangeCondition
+	rootAggregates map[string]*preloadAggregate
 	selectedFields []any
 	subTraversals  map[string]*gremlingo.GraphTraversal
+	unscoped       bool
+	useWriter      bool
 }
 
 type QueryCondition struct {
@@ -131,11 +137,20 @@ func NewQuery[T any](db *GremlinDriver) *Query[T] {
 	ids := make([]any, 0)
 	fields := schemaFor(reflect.TypeFor[T]()).selectedFields
 	labels := []any{label}
+	var err error
+	if rt := reflect.TypeFor[T](); rt.Kind() == reflect.Interface {
+		// Interface typed queries span every registered model implementing T.
+		// Without any, the query would match every vertex.
+		if labels = registeredLabelsFor(rt); len(labels) == 0 {
+			err = fmt.Errorf("no registered models implement %s", rt)
+		}
+	}
 	return &Query[T]{
 		conditions:     make([]*QueryCondition, 0),
 		db:             db,
 		debug:          os.Getenv("GSM_DEBUG") == "true",
 		debugString:    &queryAsString,
+		err:            err,
 		ids:            ids,
 		labels:         labels,
 		orderBy:        nil,
@@ -323,20 +338,27 @@ func (q *Query[T]) Find() ([]T, error) {
 	q.writeDebugString(".ToList()")
 	query := q.buildBaseQuery()
 	if len(q.selectedFields) > 0 {
-		query = ToMapTraversal(query, q.subTraversals, q.selectedFields...)
+		query = ToMapTraversal(query, q.subTraversals, q.db.timestamps.selectFields(q.selectedFields)...)
 	} else {
 		query = ToMapTraversal(query, q.subTraversals, true)
 	}
 	query = q.doOrderSkipRange(query)
-	queryResults, err := query.ToList()
+	maxRows := q.maxRows()
+	if maxRows > 0 {
+		query = query.Limit(maxRows + 1)
+	}
+	queryResults, err := q.db.readList(query, q.useWriter)
 	if err != nil {
 		return nil, err
 	}
+	if maxRows > 0 && len(queryResults) > maxRows {
+		return nil, fmt.Errorf("Find: %w (more than %d); narrow the query or call AllowGlobal", ErrTooManyRows, maxRows)
+	}
 
 	results := make([]T, 0, len(queryResults))
 	for _, result := range queryResults {
 		var v T
-		err = UnloadGremlinResultIntoStruct(&v, result)
+		err = q.unload(&v, result)
 		if err != nil {
 			return nil, err
 		}
@@ -357,12 +379,12 @@ func (q *Query[T]) Take() (T, error) {
 	q.writeDebugString(".Next()")
 	query := q.buildBaseQuery()
 	if len(q.selectedFields) > 0 {
-		query = ToMapTraversal(query, q.subTraversals, q.selectedFields...)
+		query = ToMapTraversal(query, q.subTraversals, q.db.timestamps.selectFields(q.selectedFields)...)
 	} else {
 		query = ToMapTraversal(query, q.subTraversals, true)
 	}
 	query = q.doOrderSkipRange(query)
-	result, err := query.Next()
+	result, err := q.db.readNext(query, q.useWriter)
 	if err != nil {
 		if isGremlinNotFoundErr(err) {
 			return v, gsmtypes.ErrNotFound
@@ -370,7 +392,7 @@ func (q *Query[T]) Take() (T, error) {
 		return v, err
 	}
 
-	err = UnloadGremlinResultIntoStruct(&v, result)
+	err = q.unload(&v, result)
 	if err != nil {
 		return v, err
 	}
@@ -383,28 +405,52 @@ func (q *Query[T]) Take() (T, error) {
 
 // Count returns the number of matching results
 func (q *Query[T]) Count() (int, error) {
+	if q.err != nil {
+		return 0, q.err
+	}
 	q.writeDebugString(".Count()")
 	query := q.BuildQuery().Count()
-	result, defaultVal, err := nextWithDefaultValue(query, 0)
+	results, err := q.db.readList(query, q.useWriter)
 	if err != nil {
 		return 0, err
 	}
-	if result == nil {
-		return defaultVal, nil
+	if len(results) == 0 {
+		return 0, nil
 	}
-	num, err := result.GetInt()
+	num, err := results[0].GetInt()
 	if err != nil {
 		return 0, err
 	}
 	return num, nil
 }
 
-// Delete deletes all matching results
+// Delete deletes all matching results. Models embedding gsmtypes.SoftDelete
+// are soft deleted by setting deleted_at; use HardDelete to drop them.
 func (q *Query[T]) Delete() error {
-	q.writeDebugString(".Drop().Iterate()")
-	query := q.BuildQuery()
-	err := query.Drop().Iterate()
-	return <-err
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.requireConditions("Delete"); err != nil {
+		return err
+	}
+	if !q.softDeletes() {
+		q.writeDebugString(".Drop().Iterate()")
+		return q.audited(
+			AuditDelete, nil, nil, func(db *GremlinDriver, query *gremlingo.GraphTraversal) error {
+				return db.iterate(query.Drop())
+			},
+		)
+	}
+	q.writeDebugString(".SoftDelete().Iterate()")
+	now := q.db.timestamps.now()
+	deletedAt := q.db.timestamps.value(now)
+	deleted := map[string]any{gsmtypes.DeletedAt: deletedAt}
+	return q.audited(
+		AuditUpdate, []string{gsmtypes.DeletedAt}, deleted,
+		func(db *GremlinDriver, query *gremlingo.GraphTraversal) error {
+			return db.iterate(q.touch(query.Property(cardinality.Single, gsmtypes.DeletedAt, deletedAt), now))
+		},
+	)
 }
 
 // ID finds vertex by id in a more optimized way than using where
@@ -413,18 +459,19 @@ func (q *Query[T]) ID(id any) (T, error) {
 	if q.err != nil {
 		return v, q.err
 	}
-	query := q.db.g.V(id)
+	query := q.db.g.V(q.db.dialect.NormalizeID(id))
 	if len(q.labels) > 0 {
 		query = query.HasLabel(q.labels...)
 	}
-	result, err := ToMapTraversal(query, q.subTraversals, true).Next()
+	query = q.addScopes(q.addPartitionKey(query))
+	result, err := q.db.readNext(ToMapTraversal(query, q.subTraversals, true), q.useWriter)
 	if err != nil {
 		if isGremlinNotFoundErr(err) {
 			return v, gsmtypes.ErrNotFound
 		}
 		return v, err
 	}
-	err = UnloadGremlinResultIntoStruct(&v, result)
+	err = q.unload(&v, result)
 	if err != nil {
 		return v, err
 	}
@@ -454,6 +501,9 @@ func (q *Query[T]) Updates(properties map[string]any) error {
 	if len(properties) == 0 {
 		return nil
 	}
+	if err := q.requireConditions("Updates"); err != nil {
+		return err
+	}
 	rt := reflect.TypeFor[T]()
 	if rt.Kind() == reflect.Pointer {
 		rt = rt.Elem()
@@ -480,14 +530,48 @@ func (q *Query[T]) Updates(properties map[string]any) error {
 		}
 		fieldTypes[key] = rt.FieldByIndex(field.index).Type
 	}
+	if err := validateUpdates(q.db, rt, schema, properties); err != nil {
+		return err
+	}
 
-	query := q.BuildQuery()
-	query.Property(cardinality.Single, gsmtypes.LastModified, time.Now().UTC())
-	for _, key := range keys {
-		query = q.applyPropertyUpdate(query, key, fieldTypes[key], properties[key])
+	conflicts := uniqueUpdateConflicts(q.labels, schema.uniqueGroups, properties)
+	now := q.db.timestamps.now()
+	return q.audited(
+		AuditUpdate, keys, properties, func(db *GremlinDriver, query *gremlingo.GraphTraversal) error {
+			update := query
+			if len(conflicts) > 0 {
+				// Fold the matched vertices so the guards can check all of
+				// them before any is written.
+				update = anonymousTraversal.Unfold()
+			}
+			update = q.touch(update, now)
+			for _, key := range keys {
+				update = q.applyPropertyUpdate(update, key, fieldTypes[key], properties[key])
+			}
+			if len(conflicts) == 0 {
+				return db.iterate(update)
+			}
+			results, err := db.toList(query.Fold().Coalesce(append(conflicts, update)...))
+			if err != nil {
+				return err
+			}
+			for _, result := range results {
+				if err = uniqueViolation(schema.uniqueGroups, result); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	)
+}
+
+// touch sets the last modified timestamp of the vertices reached by query to
+// now, unless T disables timestamps.
+func (q *Query[T]) touch(query *gremlingo.GraphTraversal, now time.Time) *gremlingo.GraphTraversal {
+	if schemaFor(reflect.TypeFor[T]()).noTimestamps {
+		return query
 	}
-	errChan := query.Iterate()
-	return <-errChan
+	return query.Property(cardinality.Single, q.db.timestamps.lastModified, q.db.timestamps.value(now))
 }
 
 // applyPropertyUpdate appends the Property steps for a single property to the
@@ -507,12 +591,8 @@ func (q *Query[T]) applyPropertyUpdate(
 		q.writeDebugString(propertyName)
 		q.writeDebugString(").Drop())")
 		query = query.SideEffect(anonymousTraversal.Properties(propertyName).Drop())
-		cardinality := gremlingo.Cardinality.List
-		cardinalityString := "Cardinality.List"
-		if q.db.dbDriver == Neptune {
-			cardinalityString = "Cardinality.Set"
-			cardinality = gremlingo.Cardinality.Set
-		}
+		sliceCardinality := q.db.dialect.SliceCardinality()
+		cardinalityString := fmt.Sprintf("Cardinality.%v", sliceCardinality)
 		rv := reflect.ValueOf(value)
 		sliceValue := make([]any, rv.Len())
 		for i := range rv.Len() {
@@ -526,7 +606,7 @@ func (q *Query[T]) applyPropertyUpdate(
 			q.writeDebugString(", ")
 			q.writeDebugString(fmt.Sprintf("%v", v))
 			q.writeDebugString(")")
-			query = query.Property(cardinality, propertyName, v)
+			query = query.Property(sliceCardinality, propertyName, v)
 		}
 	default:
 		q.writeDebugString(".Property(Cardinality.Single, ")
@@ -539,6 +619,24 @@ func (q *Query[T]) applyPropertyUpdate(
 	return query
 }
 
+// timestamps returns the timestamp configuration of the query's driver, or
+// the defaults for queries built without one, which only report errors.
+func (q *Query[T]) timestamps() timestamps {
+	if q.db == nil {
+		return timestampsFor(Config{})
+	}
+	return q.db.timestamps
+}
+
+// unload unloads a result of the query into v, reading the timestamps from
+// their configured property names.
+func (q *Query[T]) unload(v *T, result *gremlingo.Result) error {
+	if result != nil {
+		result = &gremlingo.Result{Data: q.db.timestamps.canonical(result.GetInterface())}
+	}
+	return unloadResult(v, result)
+}
+
 // writeDebugString writes a string to the debug string if GSM_DEBUG is set to true
 func (q *Query[T]) writeDebugString(s string) {
 	if q.debug {
@@ -559,14 +657,19 @@ func (q *Query[T]) buildBaseQuery() *gremlingo.GraphTraversal {
 	}
 	var query *gremlingo.GraphTraversal
 
+	ids := make([]any, len(q.ids))
+	for i, id := range q.ids {
+		ids[i] = q.db.dialect.NormalizeID(id)
+	}
+
 	switch {
 	case q.preTraversal != nil:
 		query = q.preTraversal.Clone()
-		if len(q.ids) > 0 {
-			query = query.HasId(q.ids...)
+		if len(ids) > 0 {
+			query = query.HasId(ids...)
 		}
-	case len(q.ids) > 0:
-		query = q.db.g.V(q.ids...)
+	case len(ids) > 0:
+		query = q.db.g.V(ids...)
 	default:
 		query = q.db.g.V()
 	}
@@ -574,8 +677,10 @@ func (q *Query[T]) buildBaseQuery() *gremlingo.GraphTraversal {
 	if len(q.labels) > 0 {
 		query = query.HasLabel(q.labels...)
 	}
+	query = q.addScopes(q.addPartitionKey(query))
 
 	q.addQueryConditions(query)
+	q.warnUnindexed()
 
 	if q.dedup {
 		query = query.Dedup()
@@ -635,7 +740,7 @@ func (q *Query[T]) addQueryConditions(query *gremlingo.GraphTraversal) { //nolin
 			query = query.Has(condition.field, gremlingo.P.Lte(condition.value))
 		case comparator.CONTAINS:
 			if strVal, ok := condition.value.(string); ok {
-				query = query.Has(condition.field, gremlingo.TextP.Containing(strVal))
+				query = query.Has(condition.field, q.db.dialect.TextContains(strVal))
 			}
 		case comparator.IN, comparator.WITHOUT:
 			var sliceValue []any
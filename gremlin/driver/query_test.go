@@ -12,7 +12,7 @@ import (
 	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
 )
 
-var dbDriver = driver.Neptune
+var dbDialect driver.Dialect = driver.NeptuneDialect{}
 
 func seedData(db *driver.GremlinDriver, data []testVertexForUtils) error {
 	for _, d := range data {
@@ -27,7 +27,7 @@ func seedData(db *driver.GremlinDriver, data []testVertexForUtils) error {
 func cleanDB() {
 	db, _ := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	<-db.G().V().Drop().Iterate()
@@ -36,7 +36,7 @@ func cleanDB() {
 func TestQuery(t *testing.T) {
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -562,7 +562,7 @@ func TestQuery(t *testing.T) {
 
 			customDbIDGenerator, _ := driver.Open(
 				DbURL, driver.Config{
-					Driver: dbDriver,
+					Dialect: dbDialect,
 					IDGenerator: func() any {
 						return testID.String()
 					},
@@ -931,7 +931,7 @@ func TestSaveReplacesSliceProperties(t *testing.T) {
 	t.Cleanup(cleanDB)
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -966,7 +966,7 @@ func TestSaveKeepsUnrelatedProperties(t *testing.T) {
 	t.Cleanup(cleanDB)
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -39,7 +39,7 @@ func (rq *RawQuery) ToList() ([]*gremlingo.Result, error) {
 	if rq.traversal == nil {
 		rq.traversal = rq.db.g.V().HasLabel(rq.label)
 	}
-	results, err := rq.traversal.ToList()
+	results, err := rq.db.toList(rq.traversal)
 	if err != nil {
 		return nil, err
 	}
@@ -51,7 +51,7 @@ func (rq *RawQuery) Next() (*gremlingo.Result, error) {
 	if rq.traversal == nil {
 		rq.traversal = rq.db.g.V().HasLabel(rq.label)
 	}
-	result, err := rq.traversal.ElementMap().Next()
+	result, err := rq.db.next(rq.traversal.ElementMap())
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,170 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+// PreloadRecursive eagerly loads a self-referencing gremlinEdge field up to
+// maxDepth levels deep, populating a nested struct tree:
+//
+//	type Folder struct {
+//		gsmtypes.Vertex
+//		Name     string   `gremlin:"name"`
+//		Children []Folder `gremlinEdge:"contains"`
+//	}
+//
+//	root, err := driver.Model[Folder](db).
+//		Where("name", comparator.EQ, "root").
+//		PreloadRecursive("Children", 3).
+//		Take()
+//
+// fieldName must be a Go field name on T whose related struct type is T
+// itself. Vertices already on the path from the root are skipped, so cycles
+// in the graph do not repeat in the tree.
+func (q *Query[T]) PreloadRecursive(fieldName string, maxDepth int) *Query[T] {
+	if q.err != nil {
+		return q
+	}
+	if maxDepth < 1 {
+		q.err = fmt.Errorf("preload recursive: max depth must be at least 1, got %d", maxDepth)
+		return q
+	}
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	if _, err := selfReferencingEdge(modelType, fieldName); err != nil {
+		q.err = fmt.Errorf("preload recursive: %w", err)
+		return q
+	}
+
+	fieldPath := strings.Repeat(fieldName+".", maxDepth-1) + fieldName
+	if _, _, err := q.mergePreloadPath(fieldPath); err != nil {
+		q.err = err
+		return q
+	}
+	for node := q.preloads[fieldName]; node != nil; node = node.children[fieldName] {
+		node.simplePath = true
+	}
+	if err := q.rebuildPreload(fieldName); err != nil {
+		q.err = err
+		return q
+	}
+	q.writeDebugString(".PreloadRecursive(")
+	q.writeDebugString(fieldName)
+	q.writeDebugString(", ")
+	q.writeDebugString(strconv.Itoa(maxDepth))
+	q.writeDebugString(")")
+	return q
+}
+
+// Descendants returns the vertices reachable from the vertex with the given id
+// by repeatedly following the self-referencing gremlinEdge field edgeField of
+// T in its declared direction. depth limits the number of hops; a depth of 0
+// or less follows the hierarchy to its leaves. The start vertex is not
+// included and every vertex is returned once.
+func Descendants[T any](db *GremlinDriver, id any, edgeField string, depth int) ([]T, error) {
+	traversal, err := hierarchyTraversal[T](db, id, edgeField, depth, false)
+	if err != nil {
+		return nil, err
+	}
+	return Model[T](db).PreQuery(traversal).Dedup().Find()
+}
+
+// Ancestors is the inverse of Descendants: it follows edgeField against its
+// declared direction, returning the parents, grandparents and so on of the
+// vertex with the given id.
+func Ancestors[T any](db *GremlinDriver, id any, edgeField string, depth int) ([]T, error) {
+	traversal, err := hierarchyTraversal[T](db, id, edgeField, depth, true)
+	if err != nil {
+		return nil, err
+	}
+	return Model[T](db).PreQuery(traversal).Dedup().Find()
+}
+
+// hierarchyTraversal builds repeat(step.simplePath()).until(loops >= depth).emit()
+// from the vertex with the given id over the edge of edgeField.
+func hierarchyTraversal[T any](
+	db *GremlinDriver,
+	id any,
+	edgeField string,
+	depth int,
+	reverse bool,
+) (*gremlingo.GraphTraversal, error) {
+	modelType := reflect.TypeFor[T]()
+	if modelType.Kind() == reflect.Pointer {
+		modelType = modelType.Elem()
+	}
+	tagOpts, err := selfReferencingEdge(modelType, edgeField)
+	if err != nil {
+		return nil, err
+	}
+	direction := tagOpts.direction
+	if reverse {
+		direction = direction.reverse()
+	}
+
+	var step *gremlingo.GraphTraversal
+	switch direction {
+	case edgeDirectionIn:
+		step = anonymousTraversal.In(tagOpts.label)
+	case edgeDirectionBoth:
+		step = anonymousTraversal.Both(tagOpts.label)
+	case edgeDirectionOut:
+		step = anonymousTraversal.Out(tagOpts.label)
+	}
+
+	traversal := db.g.V(id).Repeat(step.SimplePath())
+	if depth > 0 {
+		traversal = traversal.Until(anonymousTraversal.Loops().Is(P.Gte(depth)))
+	}
+	return traversal.Emit(), nil
+}
+
+// selfReferencingEdge validates that fieldName on modelType is a gremlinEdge
+// field relating modelType to itself and returns its parsed tag.
+func selfReferencingEdge(modelType reflect.Type, fieldName string) (gremlinEdgeTagOptions, error) {
+	if modelType.Kind() != reflect.Struct {
+		return gremlinEdgeTagOptions{}, fmt.Errorf("type %s is not a struct", modelType.Name())
+	}
+	field, ok := modelType.FieldByName(fieldName)
+	if !ok {
+		return gremlinEdgeTagOptions{}, fmt.Errorf(
+			"field %s not found on struct %s",
+			fieldName,
+			modelType.Name(),
+		)
+	}
+	edgeTag := field.Tag.Get(gsmtypes.GremlinEdgeTag)
+	if edgeTag == "" {
+		return gremlinEdgeTagOptions{}, fmt.Errorf(
+			"field %s on struct %s is missing the %s tag",
+			fieldName,
+			modelType.Name(),
+			gsmtypes.GremlinEdgeTag,
+		)
+	}
+	tagOpts, err := parseGremlinEdgeTag(edgeTag)
+	if err != nil {
+		return gremlinEdgeTagOptions{}, fmt.Errorf("field %s: %w", fieldName, err)
+	}
+	relatedType, err := edgeFieldStructType(field.Type)
+	if err != nil {
+		return gremlinEdgeTagOptions{}, fmt.Errorf("field %s: %w", fieldName, err)
+	}
+	if relatedType != modelType {
+		return gremlinEdgeTagOptions{}, fmt.Errorf(
+			"field %s on struct %s does not reference %s",
+			fieldName,
+			modelType.Name(),
+			modelType.Name(),
+		)
+	}
+	return tagOpts, nil
+}
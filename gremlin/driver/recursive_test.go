@@ -0,0 +1,219 @@
+package driver_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testFolder struct {
+	gsmtypes.Vertex
+	Name     string       `json:"name"     gremlin:"name"`
+	Children []testFolder `json:"children"                gremlinEdge:"contains"`
+	Parent   *testFolder  `json:"parent"                  gremlinEdge:"contains,in"`
+}
+
+// seedFolderTree creates root -> (docs -> (drafts -> archive), src).
+func seedFolderTree(t *testing.T, db *driver.GremlinDriver) map[string]testFolder {
+	t.Helper()
+	folders := make(map[string]testFolder)
+	for _, name := range []string{"root", "docs", "src", "drafts", "archive"} {
+		folder := testFolder{Name: name}
+		if err := driver.Create(db, &folder); err != nil {
+			t.Fatal(err)
+		}
+		folders[name] = folder
+	}
+	addEdge(t, db, folders["root"].ID, "contains", folders["docs"].ID)
+	addEdge(t, db, folders["root"].ID, "contains", folders["src"].ID)
+	addEdge(t, db, folders["docs"].ID, "contains", folders["drafts"].ID)
+	addEdge(t, db, folders["drafts"].ID, "contains", folders["archive"].ID)
+	return folders
+}
+
+func folderNames(folders []testFolder) []string {
+	names := make([]string, 0, len(folders))
+	for _, folder := range folders {
+		names = append(names, folder.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRecursive(t *testing.T) {
+	db, err := driver.Open(
+		DbURL, driver.Config{
+			Dialect: dbDialect,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run(
+		"TestDescendants", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			folders := seedFolderTree(t, db)
+
+			all, err := driver.Descendants[testFolder](db, folders["root"].ID, "Children", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := folderNames(all); len(got) != 4 {
+				t.Errorf("Expected 4 descendants, got %v", got)
+			}
+
+			twoLevels, err := driver.Descendants[testFolder](db, folders["root"].ID, "Children", 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := folderNames(twoLevels)
+			want := []string{"docs", "drafts", "src"}
+			if len(got) != len(want) {
+				t.Fatalf("Expected %v, got %v", want, got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Expected %v, got %v", want, got)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"TestAncestors", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			folders := seedFolderTree(t, db)
+
+			ancestors, err := driver.Ancestors[testFolder](db, folders["archive"].ID, "Children", 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := folderNames(ancestors)
+			want := []string{"docs", "drafts", "root"}
+			if len(got) != len(want) {
+				t.Fatalf("Expected %v, got %v", want, got)
+			}
+			for i := range want {
+				if got[i] != want[i] {
+					t.Errorf("Expected %v, got %v", want, got)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"TestPreloadRecursiveTree", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			seedFolderTree(t, db)
+
+			root, err := driver.Model[testFolder](db).
+				Where("name", comparator.EQ, "root").
+				PreloadRecursive("Children", 2).
+				Take()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := folderNames(root.Children); len(got) != 2 {
+				t.Fatalf("Expected 2 children, got %v", got)
+			}
+			for _, child := range root.Children {
+				if child.Name != "docs" {
+					continue
+				}
+				if len(child.Children) != 1 || child.Children[0].Name != "drafts" {
+					t.Fatalf("Expected docs to contain drafts, got %+v", child.Children)
+				}
+				// archive is three levels deep and beyond maxDepth
+				if len(child.Children[0].Children) != 0 {
+					t.Errorf("Expected depth to stop at 2, got %+v", child.Children[0].Children)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"TestPreloadRecursiveCycle", func(t *testing.T) {
+			t.Cleanup(cleanDB)
+			folders := seedFolderTree(t, db)
+			addEdge(t, db, folders["archive"].ID, "contains", folders["root"].ID)
+
+			root, err := driver.Model[testFolder](db).
+				Where("name", comparator.EQ, "root").
+				PreloadRecursive("Children", 5).
+				Take()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var archive *testFolder
+			for _, child := range root.Children {
+				if child.Name == "docs" {
+					archive = &child.Children[0].Children[0]
+				}
+			}
+			if archive == nil || archive.Name != "archive" {
+				t.Fatalf("Expected archive to be loaded, got %+v", archive)
+			}
+			if len(archive.Children) != 0 {
+				t.Errorf("Expected cycle back to root to be skipped, got %+v", archive.Children)
+			}
+		},
+	)
+}
+
+func TestRecursiveErrors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{
+			name: "preload zero depth",
+			run: func() error {
+				_, err := driver.Model[testFolder](nil).PreloadRecursive("Children", 0).Find()
+				return err
+			},
+		},
+		{
+			name: "preload unknown field",
+			run: func() error {
+				_, err := driver.Model[testFolder](nil).PreloadRecursive("NotAField", 2).Find()
+				return err
+			},
+		},
+		{
+			name: "preload field that does not reference itself",
+			run: func() error {
+				_, err := driver.Model[testPerson](nil).PreloadRecursive("Topics", 2).Find()
+				return err
+			},
+		},
+		{
+			name: "descendants field without gremlinEdge tag",
+			run: func() error {
+				_, err := driver.Descendants[testFolder](nil, "1", "Name", 0)
+				return err
+			},
+		},
+		{
+			name: "ancestors field that does not reference itself",
+			run: func() error {
+				_, err := driver.Ancestors[testPerson](nil, "1", "Topics", 0)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				if err := tt.run(); err == nil {
+					t.Error("Expected error, got nil")
+				}
+			},
+		)
+	}
+}
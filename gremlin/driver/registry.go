@@ -0,0 +1,123 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// modelRegistry maps vertex labels to the Go struct types registered for
+// them with RegisterModel.
+var modelRegistry sync.Map // string -> reflect.Type
+
+// RegisterModel registers T as the Go type for vertices with T's label so
+// interface typed queries (e.g. Query[any] or Query[Entity]) and path hops can
+// unload each row into its concrete struct. Registering a second type for an
+// already registered label returns an error.
+func RegisterModel[T any]() error {
+	rt := reflect.TypeFor[T]()
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if rt.Kind() != reflect.Struct {
+		return fmt.Errorf("register model: type %s is not a struct", rt)
+	}
+	label := schemaFor(rt).zeroLabel
+	if existing, loaded := modelRegistry.LoadOrStore(label, rt); loaded && existing != rt {
+		return fmt.Errorf("register model: label %q is already registered to %s", label, existing)
+	}
+	return nil
+}
+
+// FindPolymorphic runs a vertex traversal and unloads every result into the
+// model registered for its label. Values are pointers to the registered
+// structs; vertices with unregistered labels are returned as map[string]any.
+// It fails when no model is registered.
+//
+//	feed, err := driver.FindPolymorphic(db, db.G().V().HasLabel("post", "comment", "like"))
+func FindPolymorphic(db *GremlinDriver, query *gremlingo.GraphTraversal) ([]any, error) {
+	return Model[any](db).PreQuery(query).Labels().Find()
+}
+
+// lookupModel returns the struct type registered for label.
+func lookupModel(label string) (reflect.Type, bool) {
+	modelType, ok := modelRegistry.Load(label)
+	if !ok {
+		return nil, false
+	}
+	return modelType.(reflect.Type), true //nolint:errcheck // registry only stores reflect.Type
+}
+
+// registeredLabelsFor returns the sorted labels of registered models that
+// can be stored in the interface type rt.
+func registeredLabelsFor(rt reflect.Type) []any {
+	var labels []string
+	modelRegistry.Range(func(key, value any) bool {
+		modelType := value.(reflect.Type) //nolint:errcheck // registry only stores reflect.Type
+		if reflect.PointerTo(modelType).Implements(rt) || modelType.Implements(rt) {
+			labels = append(labels, key.(string)) //nolint:errcheck // registry keys are labels
+		}
+		return true
+	})
+	slices.Sort(labels)
+	return SliceToAnySlice(labels)
+}
+
+// unloadResult unloads a gremlin value map result into v. Interface typed
+// values are resolved through the model registry by the row's label.
+func unloadResult[T any](v *T, result *gremlingo.Result) error {
+	if reflect.TypeFor[T]().Kind() != reflect.Interface {
+		return UnloadGremlinResultIntoStruct(v, result)
+	}
+	if result == nil {
+		return errors.New("gremlin result is nil")
+	}
+	mapResult, ok := result.GetInterface().(map[any]any)
+	if !ok {
+		return errors.New("result is not a map")
+	}
+	return unloadPolymorphic(v, mapResult)
+}
+
+// unloadPolymorphic stores the model registered for the map's label in the
+// interface pointed to by v, preferring a pointer to the struct. Unregistered
+// labels fall back to a map[string]any of the properties when it fits v.
+func unloadPolymorphic[T any](v *T, mapResult map[any]any) error {
+	rt := reflect.TypeFor[T]()
+	label, _ := mapResult["label"].(string)
+	target := reflect.ValueOf(v).Elem()
+
+	if modelType, ok := lookupModel(label); ok {
+		structPointer := reflect.New(modelType)
+		if err := unloadGremlinMapIntoStruct(structPointer.Interface(), mapResult); err != nil {
+			return err
+		}
+		switch {
+		case structPointer.Type().AssignableTo(rt):
+			target.Set(structPointer)
+		case modelType.AssignableTo(rt):
+			target.Set(structPointer.Elem())
+		default:
+			return fmt.Errorf("model %s registered for label %q does not implement %s", modelType, label, rt)
+		}
+		return nil
+	}
+
+	properties := make(map[string]any, len(mapResult))
+	for key, value := range mapResult {
+		keyStr, ok := key.(string)
+		if !ok {
+			return errors.New("gremlin key is not a string")
+		}
+		properties[keyStr] = value
+	}
+	if !reflect.TypeOf(properties).AssignableTo(rt) {
+		return fmt.Errorf("no model registered for label %q", label)
+	}
+	target.Set(reflect.ValueOf(properties))
+	return nil
+}
@@ -0,0 +1,185 @@
+package driver_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testFeedItem interface {
+	GetVertexID() any
+	FeedText() string
+}
+
+type testFeedPost struct {
+	gsmtypes.Vertex
+	Title string `json:"title" gremlin:"title"`
+}
+
+func (p *testFeedPost) Label() string    { return "feed_post" }
+func (p *testFeedPost) FeedText() string { return "post: " + p.Title }
+
+type testFeedComment struct {
+	gsmtypes.Vertex
+	Body string `json:"body" gremlin:"body"`
+}
+
+func (c *testFeedComment) Label() string    { return "feed_comment" }
+func (c *testFeedComment) FeedText() string { return "comment: " + c.Body }
+
+type testFeedPostDuplicate struct {
+	gsmtypes.Vertex
+}
+
+func (p *testFeedPostDuplicate) Label() string { return "feed_post" }
+
+func registerFeedModels(t *testing.T) {
+	t.Helper()
+	if err := driver.RegisterModel[testFeedPost](); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.RegisterModel[testFeedComment](); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testUnimplemented is an interface no registered model implements.
+type testUnimplemented interface {
+	Unimplemented()
+}
+
+func TestInterfaceQueryWithoutModels(t *testing.T) {
+	t.Parallel()
+	server := &recordedServer{}
+	db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+	_, err := driver.Model[testUnimplemented](db).Find()
+	if err == nil || !strings.Contains(err.Error(), "no registered models implement") {
+		t.Errorf("Expected a no registered models error from Find, got %v", err)
+	}
+	if err = driver.Model[testUnimplemented](db).Delete(); err == nil {
+		t.Error("Expected Delete to fail")
+	}
+	if len(server.requests) != 0 {
+		t.Errorf("Expected no requests, got %d", len(server.requests))
+	}
+}
+
+func TestRegisterModel(t *testing.T) {
+	t.Parallel()
+	registerFeedModels(t)
+
+	// registering the same type again is a no-op
+	if err := driver.RegisterModel[testFeedPost](); err != nil {
+		t.Errorf("Expected re-registering the same model to succeed, got %v", err)
+	}
+	if err := driver.RegisterModel[testFeedPostDuplicate](); err == nil {
+		t.Error("Expected error registering a second type for the same label")
+	}
+	if err := driver.RegisterModel[string](); err == nil {
+		t.Error("Expected error registering a non-struct type")
+	}
+}
+
+func TestUnloadPolymorphic(t *testing.T) {
+	t.Parallel()
+	registerFeedModels(t)
+
+	var item testFeedItem
+	err := driver.UnloadPolymorphicForTest(&item, map[any]any{
+		"id": "1", "label": "feed_comment", "body": "nice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment, ok := item.(*testFeedComment)
+	if !ok {
+		t.Fatalf("Expected *testFeedComment, got %T", item)
+	}
+	if comment.Body != "nice" || comment.ID != "1" {
+		t.Errorf("Expected comment to be unloaded, got %+v", comment)
+	}
+
+	var untyped any
+	err = driver.UnloadPolymorphicForTest(&untyped, map[any]any{"label": "unregistered", "name": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if properties, ok := untyped.(map[string]any); !ok || properties["name"] != "x" {
+		t.Errorf("Expected unregistered label to unload into a map, got %+v", untyped)
+	}
+
+	err = driver.UnloadPolymorphicForTest(&item, map[any]any{"label": "unregistered"})
+	if err == nil {
+		t.Error("Expected error for unregistered label with a non-map interface")
+	}
+}
+
+func TestPolymorphicQueries(t *testing.T) {
+	registerFeedModels(t)
+	db, err := driver.Open(
+		DbURL, driver.Config{
+			Dialect: dbDialect,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanDB)
+
+	post := testFeedPost{Title: "hello"}
+	if err := driver.Create(db, &post); err != nil {
+		t.Fatal(err)
+	}
+	comment := testFeedComment{Body: "welcome"}
+	if err := driver.Create(db, &comment); err != nil {
+		t.Fatal(err)
+	}
+	other := testVertex{Name: "not in the feed"}
+	if err := driver.Create(db, &other); err != nil {
+		t.Fatal(err)
+	}
+
+	feed, err := driver.Model[testFeedItem](db).Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feed) != 2 {
+		t.Fatalf("Expected 2 feed items, got %d", len(feed))
+	}
+	texts := map[string]bool{}
+	for _, item := range feed {
+		texts[item.FeedText()] = true
+	}
+	if !texts["post: hello"] || !texts["comment: welcome"] {
+		t.Errorf("Expected typed feed items, got %v", texts)
+	}
+
+	results, err := driver.FindPolymorphic(db, db.G().V())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	for _, result := range results {
+		switch value := result.(type) {
+		case *testFeedPost:
+			if value.Title != "hello" {
+				t.Errorf("Expected post title hello, got %s", value.Title)
+			}
+		case *testFeedComment:
+			if value.Body != "welcome" {
+				t.Errorf("Expected comment body welcome, got %s", value.Body)
+			}
+		case map[string]any:
+			if value["name"] != "not in the feed" {
+				t.Errorf("Expected unregistered vertex properties, got %+v", value)
+			}
+		default:
+			t.Errorf("Unexpected result type %T", result)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package driver
+
+import (
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+	defaultRetryMultiplier     = 2
+)
+
+// RetryPolicy retries operations that fail with transient errors. Reads
+// (Find, Take, Count and ID) are retried individually; Transaction re-runs
+// its whole callback in a fresh transaction, so the callback must be safe to
+// run more than once. Writes outside a transaction are never retried.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry (default 100ms).
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between attempts (default 5s).
+	MaxBackoff time.Duration
+	// Multiplier grows the delay after every attempt (default 2).
+	Multiplier float64
+	// Retryable classifies errors worth retrying (default IsTransientError).
+	Retryable func(err error) bool
+	// OnRetry is called before every retry, e.g. to record metrics.
+	OnRetry func(operation string, attempt int, delay time.Duration, err error)
+}
+
+// withDefaults returns a copy of the policy with unset fields defaulted.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = defaultRetryMultiplier
+	}
+	if p.Retryable == nil {
+		p.Retryable = IsTransientError
+	}
+	return p
+}
+
+// backoff returns the jittered delay before retry number attempt (starting
+// at 1): half the exponential delay plus a random amount up to the other
+// half, so concurrent clients do not retry in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.InitialBackoff)
+	for range attempt - 1 {
+		delay *= p.Multiplier
+		if delay >= float64(p.MaxBackoff) {
+			break
+		}
+	}
+	delay = min(delay, float64(p.MaxBackoff))
+	half := delay / 2
+	return time.Duration(half + rand.Float64()*half) //nolint:gosec // jitter needs no crypto randomness
+}
+
+// IsTransientError reports whether err may succeed when retried: a dropped
+// or refused connection, a conflict with a concurrent write, throttling, or
+// a temporary server failure.
+func IsTransientError(err error) bool {
+	err = classifyError(err)
+	if errors.Is(err, ErrConnection) || errors.Is(err, ErrConflict) || errors.Is(err, ErrThrottled) {
+		return true
+	}
+	var gremlinErr *GremlinError
+	return errors.As(err, &gremlinErr) && gremlinErr.StatusCode == statusServerErrorTemporary
+}
+
+// retry runs fn, retrying it under the driver's retry policy while it fails
+// with a retryable error. operation names fn in logs. Waiting for the next
+// attempt stops when the driver's context is done, returning its error.
+func (driver *GremlinDriver) retry(operation string, fn func() error) error {
+	policy := driver.retryPolicy
+	if policy == nil {
+		return fn()
+	}
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+			return err
+		}
+		delay := policy.backoff(attempt)
+		driver.logger.Warnf(
+			"Retrying %s in %s (attempt %d of %d): %v",
+			operation, delay, attempt+1, policy.MaxAttempts, err,
+		)
+		if policy.OnRetry != nil {
+			policy.OnRetry(operation, attempt, delay, err)
+		}
+		select {
+		case <-time.After(delay):
+		case <-driver.Context().Done():
+			return driver.Context().Err()
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package driver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+// flakyServer fails the first failures requests with err, then answers with
+// response.
+type flakyServer struct {
+	requests int
+	failures int
+	err      error
+	response []any
+}
+
+func (s *flakyServer) submit(string, map[string]any, map[string]any) ([]*gremlingo.Result, error) {
+	s.requests++
+	if s.requests <= s.failures {
+		return nil, s.err
+	}
+	results := make([]*gremlingo.Result, len(s.response))
+	for i, data := range s.response {
+		results[i] = &gremlingo.Result{Data: data}
+	}
+	return results, nil
+}
+
+var errConcurrentModification = errors.New(
+	"E0502: error in read loop, error message '{\"code\":\"ConcurrentModificationException\"}'. statusCode: 500",
+)
+
+func TestRetryPolicy(t *testing.T) {
+	t.Parallel()
+	topic := map[any]any{"id": "1", "label": "test_topic", "title": "graphs"}
+	policy := driver.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+
+	t.Run(
+		"RetriesTransientReadErrors", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 2, err: errConcurrentModification, response: []any{topic}}
+			var retries []int
+			retryPolicy := policy
+			retryPolicy.OnRetry = func(operation string, attempt int, _ time.Duration, _ error) {
+				if operation != "read" {
+					t.Errorf("Expected read operation, got %s", operation)
+				}
+				retries = append(retries, attempt)
+			}
+			db := driver.NewScriptDriverForTest(driver.Config{Retry: &retryPolicy}, server.submit)
+
+			topics, err := driver.Model[testTopic](db).Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(topics) != 1 {
+				t.Errorf("Expected 1 topic, got %d", len(topics))
+			}
+			if server.requests != 3 {
+				t.Errorf("Expected 3 requests, got %d", server.requests)
+			}
+			if len(retries) != 2 || retries[0] != 1 || retries[1] != 2 {
+				t.Errorf("Expected OnRetry for attempts [1 2], got %v", retries)
+			}
+		},
+	)
+
+	t.Run(
+		"GivesUpAfterMaxAttempts", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 5, err: errConcurrentModification}
+			db := driver.NewScriptDriverForTest(driver.Config{Retry: &policy}, server.submit)
+
+			_, err := driver.Model[testTopic](db).Take()
+			if !errors.Is(err, errConcurrentModification) {
+				t.Errorf("Expected the last error to be returned, got %v", err)
+			}
+			if server.requests != 3 {
+				t.Errorf("Expected 3 requests, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"DoesNotRetryPermanentErrors", func(t *testing.T) {
+			t.Parallel()
+			invalid := errors.New("E0502: error in read loop, error message 'No such property'. statusCode: 597")
+			server := &flakyServer{failures: 1, err: invalid, response: []any{topic}}
+			db := driver.NewScriptDriverForTest(driver.Config{Retry: &policy}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); err == nil {
+				t.Error("Expected the server error to be returned")
+			}
+			if server.requests != 1 {
+				t.Errorf("Expected 1 request, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"DoesNotRetryWrites", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 1, err: errConcurrentModification}
+			db := driver.NewScriptDriverForTest(driver.Config{Retry: &policy}, server.submit)
+
+			if err := driver.Model[testTopic](db).IDs("1").Delete(); err == nil {
+				t.Error("Expected the write error to be returned")
+			}
+			if server.requests != 1 {
+				t.Errorf("Expected 1 request, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"StopsWhenContextIsDone", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 5, err: errConcurrentModification}
+			slow := driver.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour}
+			db := driver.NewScriptDriverForTest(driver.Config{Retry: &slow}, server.submit)
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(10*time.Millisecond, cancel)
+
+			_, err := driver.Model[testTopic](db.WithContext(ctx)).Find()
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected context.Canceled, got %v", err)
+			}
+			if server.requests != 1 {
+				t.Errorf("Expected 1 request, got %d", server.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"DisabledByDefault", func(t *testing.T) {
+			t.Parallel()
+			server := &flakyServer{failures: 1, err: errConcurrentModification, response: []any{topic}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); err == nil {
+				t.Error("Expected the transient error to be returned")
+			}
+			if server.requests != 1 {
+				t.Errorf("Expected 1 request, got %d", server.requests)
+			}
+		},
+	)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	t.Parallel()
+	policy := driver.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 3, want: 400 * time.Millisecond},
+		{attempt: 10, want: time.Second},
+	}
+	for _, tt := range tests {
+		for range 20 {
+			got := driver.RetryBackoffForTest(policy, tt.attempt)
+			if got < tt.want/2 || got > tt.want {
+				t.Errorf(
+					"Attempt %d: expected backoff in [%s, %s], got %s",
+					tt.attempt, tt.want/2, tt.want, got,
+				)
+			}
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "Nil", err: nil, want: false},
+		{name: "NeptuneConflict", err: errConcurrentModification, want: true},
+		{
+			name: "JanusGraphLock",
+			err:  errors.New("E0502: error message 'org.janusgraph.diskstorage.locking.PermanentLockingException: Local lock contention'"),
+			want: true,
+		},
+		{
+			name: "CosmosThrottled",
+			err:  errors.New("E0502: error message 'RequestRateTooLarge'. statusCode: 500"),
+			want: true,
+		},
+		{name: "ConnectionRefused", err: errors.New("E0104: no successful connections could be made"), want: true},
+		{
+			name: "WrappedConnectionError",
+			err:  errors.New("failed to begin transaction: E0104: no successful connections could be made"),
+			want: true,
+		},
+		{name: "NotFound", err: errors.New("E0903: there are no results left"), want: false},
+		{name: "InvalidQuery", err: errors.New("E0502: error message 'No such property'. statusCode: 597"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				if got := driver.IsTransientError(tt.err); got != tt.want {
+					t.Errorf("Expected %v, got %v", tt.want, got)
+				}
+			},
+		)
+	}
+}
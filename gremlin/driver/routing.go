@@ -0,0 +1,162 @@
+package driver
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// readerRetryInterval is how long a reader that failed with a connection
+// error is skipped before it is tried again.
+const readerRetryInterval = 30 * time.Second
+
+// reader is a read replica endpoint.
+type reader struct {
+	url     string
+	remote  *gremlingo.DriverRemoteConnection
+	scripts scriptSubmitter
+	state   *connectionState
+	// unhealthyUntil is the unix nano time until which the reader is skipped.
+	unhealthyUntil atomic.Int64
+}
+
+func (r *reader) healthy(now time.Time) bool {
+	return now.UnixNano() >= r.unhealthyUntil.Load()
+}
+
+func (r *reader) markUnhealthy(now time.Time) {
+	r.unhealthyUntil.Store(now.Add(readerRetryInterval).UnixNano())
+}
+
+func (r *reader) markHealthy() {
+	r.unhealthyUntil.Store(0)
+}
+
+// toList submits traversal to the reader. Traversals are built on the
+// writer's traversal source, so their bytecode is rebound to the reader's
+// connection first.
+func (r *reader) toList(traversal *gremlingo.GraphTraversal, dialect Dialect) ([]*gremlingo.Result, error) {
+	if r.scripts != nil {
+		return submitScript(r.scripts, traversal, dialect)
+	}
+	results, err := gremlingo.NewGraphTraversal(nil, gremlingo.NewBytecode(traversal.Bytecode), r.remote).ToList()
+	return results, classifyError(err)
+}
+
+// readerPool spreads reads across readers round-robin, skipping readers
+// that recently failed.
+type readerPool struct {
+	readers []*reader
+	next    atomic.Uint64
+}
+
+// pick returns the next healthy reader, or nil if there is none.
+func (p *readerPool) pick(now time.Time) *reader {
+	if p == nil {
+		return nil
+	}
+	for range p.readers {
+		r := p.readers[(p.next.Add(1)-1)%uint64(len(p.readers))]
+		if r.healthy(now) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (p *readerPool) close() {
+	if p == nil {
+		return
+	}
+	for _, r := range p.readers {
+		if r.remote != nil {
+			r.remote.Close()
+		}
+	}
+}
+
+// UseWriter sends the query to the writer even when readers are configured,
+// for reads that must observe the caller's own writes.
+func (q *Query[T]) UseWriter() *Query[T] {
+	q.writeDebugString(".UseWriter()")
+	q.useWriter = true
+	return q
+}
+
+// readList runs a read-only traversal on a healthy reader. Without readers,
+// when useWriter is set, or when the reader's connection fails, the read
+// goes to the writer. Transient failures are retried under the retry policy.
+func (driver *GremlinDriver) readList(
+	traversal *gremlingo.GraphTraversal,
+	useWriter bool,
+) ([]*gremlingo.Result, error) {
+	var results []*gremlingo.Result
+	err := driver.retry(
+		"read", func() error {
+			var err error
+			results, err = driver.routeList(traversal, useWriter)
+			return err
+		},
+	)
+	return results, err
+}
+
+// routeList is a single attempt of readList.
+func (driver *GremlinDriver) routeList(
+	traversal *gremlingo.GraphTraversal,
+	useWriter bool,
+) ([]*gremlingo.Result, error) {
+	if useWriter {
+		return driver.toList(traversal)
+	}
+	r := driver.readers.pick(time.Now())
+	if r == nil {
+		return driver.toList(traversal)
+	}
+	prepared, err := driver.prepare(traversal)
+	if err != nil {
+		return nil, err
+	}
+	prepared, timeout := driver.applyTimeout(prepared)
+	results, err := withDeadline(
+		timeout, func() ([]*gremlingo.Result, error) {
+			results, err := r.toList(prepared, driver.dialect)
+			r.state.observe(err)
+			return results, err
+		},
+	)
+	if errors.Is(err, ErrConnection) {
+		driver.logger.Warnf("Reader %s is unhealthy, reading from the writer: %v", r.url, err)
+		r.markUnhealthy(time.Now())
+		return driver.toList(traversal)
+	}
+	return results, err
+}
+
+// readNext is readList returning only the first result.
+func (driver *GremlinDriver) readNext(
+	traversal *gremlingo.GraphTraversal,
+	useWriter bool,
+) (*gremlingo.Result, error) {
+	if useWriter || driver.readers == nil {
+		var result *gremlingo.Result
+		err := driver.retry(
+			"read", func() error {
+				var err error
+				result, err = driver.next(traversal)
+				return err
+			},
+		)
+		return result, err
+	}
+	results, err := driver.readList(traversal, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, errGremlinNotFound
+	}
+	return results[0], nil
+}
@@ -0,0 +1,127 @@
+package driver_test
+
+import (
+	"errors"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+// countingServer answers every request with the same response.
+type countingServer struct {
+	requests int
+	response []any
+	err      error
+}
+
+func (s *countingServer) submit(string, map[string]any, map[string]any) ([]*gremlingo.Result, error) {
+	s.requests++
+	if s.err != nil {
+		return nil, s.err
+	}
+	results := make([]*gremlingo.Result, len(s.response))
+	for i, data := range s.response {
+		results[i] = &gremlingo.Result{Data: data}
+	}
+	return results, nil
+}
+
+func TestReadWriteRouting(t *testing.T) {
+	t.Parallel()
+	topic := map[any]any{"id": "1", "label": "test_topic", "title": "graphs"}
+
+	t.Run(
+		"ReadsRoundRobinAcrossReaders", func(t *testing.T) {
+			t.Parallel()
+			writer := &countingServer{response: []any{"1"}}
+			first := &countingServer{response: []any{topic}}
+			second := &countingServer{response: []any{topic}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, writer.submit, first.submit, second.submit)
+
+			for range 4 {
+				if _, err := driver.Model[testTopic](db).Find(); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := driver.Model[testTopic](db).Take(); err != nil {
+				t.Fatal(err)
+			}
+			if first.requests != 3 || second.requests != 2 {
+				t.Errorf("Expected reads split 3/2, got %d/%d", first.requests, second.requests)
+			}
+			if writer.requests != 0 {
+				t.Errorf("Expected no reads on the writer, got %d", writer.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"WritesAndUseWriterGoToWriter", func(t *testing.T) {
+			t.Parallel()
+			writer := &countingServer{response: []any{"1"}}
+			replica := &countingServer{response: []any{topic}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, writer.submit, replica.submit)
+
+			if err := driver.Create(db, &testTopic{Title: "graphs"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := driver.Model[testTopic](db).IDs("1").Update("title", "golang"); err != nil {
+				t.Fatal(err)
+			}
+			if err := driver.Model[testTopic](db).IDs("1").Delete(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := driver.Model[testTopic](db).UseWriter().Count(); err != nil {
+				t.Fatal(err)
+			}
+			if writer.requests != 4 {
+				t.Errorf("Expected 4 requests on the writer, got %d", writer.requests)
+			}
+			if replica.requests != 0 {
+				t.Errorf("Expected no requests on the reader, got %d", replica.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"UnhealthyReaderFallsBackToWriter", func(t *testing.T) {
+			t.Parallel()
+			writer := &countingServer{response: []any{topic}}
+			broken := &countingServer{err: errors.New("E0104: no successful connections could be made: dial tcp")}
+			db := driver.NewScriptDriverForTest(driver.Config{}, writer.submit, broken.submit)
+
+			for range 3 {
+				topics, err := driver.Model[testTopic](db).Find()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(topics) != 1 {
+					t.Fatalf("Expected 1 topic, got %d", len(topics))
+				}
+			}
+			if broken.requests != 1 {
+				t.Errorf("Expected the unhealthy reader to be skipped after one failure, got %d requests", broken.requests)
+			}
+			if writer.requests != 3 {
+				t.Errorf("Expected 3 reads on the writer, got %d", writer.requests)
+			}
+		},
+	)
+
+	t.Run(
+		"ServerErrorsAreNotRetried", func(t *testing.T) {
+			t.Parallel()
+			writer := &countingServer{response: []any{topic}}
+			rejecting := &countingServer{err: errors.New("E0502: error in read loop, error message 'bad'. statusCode: 597")}
+			db := driver.NewScriptDriverForTest(driver.Config{}, writer.submit, rejecting.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); err == nil {
+				t.Error("Expected the reader's server error to be returned")
+			}
+			if writer.requests != 0 {
+				t.Errorf("Expected no fallback to the writer, got %d requests", writer.requests)
+			}
+		},
+	)
+}
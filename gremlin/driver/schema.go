@@ -2,6 +2,7 @@ package driver
 
 import (
 	"reflect"
+	"slices"
 	"sync"
 
 	"github.com/gobeam/stringy"
@@ -22,7 +23,19 @@ type fieldSchema struct {
 	tagName         string
 	subTraversalTag string
 	omitEmpty       bool
-	isEdge          bool
+	partitionKey    bool
+	// indexed is set by the index and single-field unique tag options and
+	// mixed by mixed; see AutoMigrate.
+	indexed bool
+	mixed   bool
+	// uniqueGroup names the unique constraint the field belongs to: its own
+	// tag name for unique, or the group for unique=<group>.
+	uniqueGroup string
+	// validation holds the gsmvalidate rules, or validationErr the reason
+	// the tag could not be parsed.
+	validation    []validationRule
+	validationErr error
+	isEdge        bool
 }
 
 // typeSchema holds everything the driver needs to know about a model type.
@@ -45,6 +58,28 @@ type typeSchema struct {
 	unloadFields []fieldSchema
 	// mapFields drives structToMap for create/update.
 	mapFields []fieldSchema
+	// partitionKey is the mapFields entry tagged partitionkey, or nil.
+	partitionKey *fieldSchema
+	// uniqueGroups are the unique constraints declared with the unique tag
+	// option, in field order.
+	uniqueGroups []uniqueGroup
+	// softDelete reports whether the type embeds gsmtypes.SoftDelete, or
+	// otherwise implements gsmtypes.SoftDeleteType.
+	softDelete bool
+	// defaultScope reports whether the type implements DefaultScope.
+	defaultScope bool
+	// noTimestamps reports whether the type embeds gsmtypes.NoTimestamps,
+	// or otherwise implements gsmtypes.NoTimestampsType.
+	noTimestamps bool
+	// auditable reports whether the type implements Auditable.
+	auditable bool
+}
+
+// uniqueGroup is a set of properties whose values must together be unique
+// among vertices with the same label.
+type uniqueGroup struct {
+	name   string
+	fields []string
 }
 
 // schemaFor returns the cached schema for rt, computing it on first use.
@@ -75,6 +110,10 @@ func buildSchema(rt reflect.Type) *typeSchema {
 	schema := &typeSchema{
 		snakeName:          stringy.New(rt.Name()).SnakeCase().ToLower(),
 		implementsUnmapped: typeImplementsUnmappedProperties(rt),
+		softDelete:         typeImplementsSoftDelete(rt),
+		defaultScope:       typeImplementsDefaultScope(rt),
+		noTimestamps:       typeDisablesTimestamps(rt),
+		auditable:          typeIsAuditable(rt),
 	}
 	schema.zeroLabel = zeroValueLabel(rt, schema.snakeName)
 	if rt.Kind() != reflect.Struct {
@@ -87,6 +126,13 @@ func buildSchema(rt reflect.Type) *typeSchema {
 	}
 	schema.unloadFields = collectUnloadFields(rt, nil)
 	schema.mapFields = collectMapFields(rt, nil)
+	for i := range schema.mapFields {
+		if schema.mapFields[i].partitionKey {
+			schema.partitionKey = &schema.mapFields[i]
+			break
+		}
+	}
+	schema.uniqueGroups = collectUniqueGroups(schema.mapFields)
 	return schema
 }
 
@@ -229,17 +275,53 @@ func collectMapFields(rt reflect.Type, index []int) []fieldSchema {
 		if tagParts.unmapped {
 			continue
 		}
+		validation, validationErr := parseValidateTag(field.Tag.Get(gsmtypes.ValidateTag))
 
 		fields = append(fields, fieldSchema{
-			index:     fieldIndex,
-			goName:    field.Name,
-			tagName:   tagParts.name,
-			omitEmpty: tagParts.omitEmpty,
+			index:         fieldIndex,
+			goName:        field.Name,
+			tagName:       tagParts.name,
+			omitEmpty:     tagParts.omitEmpty,
+			partitionKey:  tagParts.partitionKey,
+			indexed:       tagParts.index || (tagParts.unique && tagParts.uniqueGroup == ""),
+			mixed:         tagParts.mixed,
+			uniqueGroup:   uniqueGroupName(tagParts),
+			validation:    validation,
+			validationErr: validationErr,
 		})
 	}
 	return fields
 }
 
+// uniqueGroupName returns the unique constraint a field with tag options
+// opts belongs to, or "" when it has none.
+func uniqueGroupName(opts gremlinTagOptions) string {
+	switch {
+	case opts.uniqueGroup != "":
+		return opts.uniqueGroup
+	case opts.unique:
+		return opts.name
+	}
+	return ""
+}
+
+// collectUniqueGroups gathers the unique constraints of fields.
+func collectUniqueGroups(fields []fieldSchema) []uniqueGroup {
+	var groups []uniqueGroup
+	for _, field := range fields {
+		if field.uniqueGroup == "" {
+			continue
+		}
+		i := slices.IndexFunc(groups, func(g uniqueGroup) bool { return g.name == field.uniqueGroup })
+		if i < 0 {
+			groups = append(groups, uniqueGroup{name: field.uniqueGroup})
+			i = len(groups) - 1
+		}
+		groups[i].fields = append(groups[i].fields, field.tagName)
+	}
+	return groups
+}
+
 // childIndex returns a fresh index path for field i nested under parent.
 func childIndex(parent []int, i int) []int {
 	index := make([]int, len(parent)+1)
@@ -0,0 +1,100 @@
+package driver
+
+import (
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+// DefaultScope is implemented by models whose vertices should be filtered
+// out of every query unless it is Unscoped, for example to hide inactive
+// records. DefaultScope returns a new anonymous filter traversal on each
+// call, which is applied like WhereTraversal to queries of the model and to
+// the vertices preloaded into fields of its type:
+//
+//	func (Post) DefaultScope() *gremlingo.GraphTraversal {
+//		return gremlingo.T__.Has("published", true)
+//	}
+type DefaultScope interface {
+	DefaultScope() *gremlingo.GraphTraversal
+}
+
+var defaultScopeType = reflect.TypeFor[DefaultScope]()
+
+// typeImplementsDefaultScope reports whether rt is a struct implementing
+// DefaultScope with a value or pointer receiver.
+func typeImplementsDefaultScope(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && reflect.PointerTo(rt).Implements(defaultScopeType)
+}
+
+// defaultScopeOf returns the default scope of the model type rt, or nil when
+// it has none.
+func defaultScopeOf(rt reflect.Type) *gremlingo.GraphTraversal {
+	if rt.Kind() == reflect.Pointer {
+		rt = rt.Elem()
+	}
+	if !schemaFor(rt).defaultScope {
+		return nil
+	}
+	// reflect.New covers both value and pointer receivers.
+	return reflect.New(rt).Interface().(DefaultScope).DefaultScope() //nolint:errcheck // checked by the schema
+}
+
+// Scopes applies reusable query modifiers in order, so common filters can be
+// shared between queries:
+//
+//	func Active(q *driver.Query[User]) *driver.Query[User] {
+//		return q.Where("status", comparator.EQ, "active")
+//	}
+//
+//	users, err := driver.Model[User](db).Scopes(Active, InTenant(id)).Find()
+func (q *Query[T]) Scopes(scopes ...func(*Query[T]) *Query[T]) *Query[T] {
+	for _, scope := range scopes {
+		q = scope(q)
+	}
+	return q
+}
+
+// Unscoped includes soft-deleted vertices and skips default scopes, in the
+// query and in its preloads and aggregates.
+func (q *Query[T]) Unscoped() *Query[T] {
+	q.writeDebugString(".Unscoped()")
+	q.unscoped = true
+	if err := q.rebuildPreloads(); err != nil {
+		q.err = err
+	}
+	return q
+}
+
+// addScopes applies T's soft delete filter and default scope.
+func (q *Query[T]) addScopes(query *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+	query = q.addSoftDeleteFilter(query)
+	if q.unscoped {
+		return query
+	}
+	if scope := defaultScopeOf(reflect.TypeFor[T]()); scope != nil {
+		query = query.Where(scope)
+	}
+	return query
+}
+
+// scopeRelated applies the soft delete filter and default scope of
+// relatedType to a preload traversal reaching it, unless the query is
+// unscoped.
+func scopeRelated(
+	traversal *gremlingo.GraphTraversal,
+	relatedType reflect.Type,
+	unscoped bool,
+) *gremlingo.GraphTraversal {
+	if unscoped {
+		return traversal
+	}
+	if schemaFor(relatedType).softDelete {
+		traversal = traversal.HasNot(gsmtypes.DeletedAt)
+	}
+	if scope := defaultScopeOf(relatedType); scope != nil {
+		traversal = traversal.Where(scope)
+	}
+	return traversal
+}
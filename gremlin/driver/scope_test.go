@@ -0,0 +1,122 @@
+package driver_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testPublishedPost struct {
+	gsmtypes.Vertex
+	Title     string `json:"title"     gremlin:"title"`
+	Published bool   `json:"published" gremlin:"published"`
+}
+
+func (testPublishedPost) DefaultScope() *gremlingo.GraphTraversal {
+	return gremlingo.T__.Has("published", true)
+}
+
+type testBlog struct {
+	gsmtypes.Vertex
+	Name  string              `json:"name"  gremlin:"name"`
+	Posts []testPublishedPost `json:"posts" gremlinEdge:"publishes"`
+}
+
+func titled(title string) func(*driver.Query[testPublishedPost]) *driver.Query[testPublishedPost] {
+	return func(q *driver.Query[testPublishedPost]) *driver.Query[testPublishedPost] {
+		return q.Where("title", comparator.EQ, title)
+	}
+}
+
+func limited(q *driver.Query[testPublishedPost]) *driver.Query[testPublishedPost] {
+	return q.Limit(5)
+}
+
+func TestScopes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func(db *driver.GremlinDriver) error
+		// scoped reports whether the default scope should be in the script
+		scoped bool
+	}{
+		{
+			name: "Find",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testPublishedPost](db).Find()
+				return err
+			},
+			scoped: true,
+		},
+		{
+			name: "ID",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testPublishedPost](db).ID("1")
+				return err
+			},
+			scoped: true,
+		},
+		{
+			name: "Unscoped",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testPublishedPost](db).Unscoped().Find()
+				return err
+			},
+		},
+		{
+			name: "Preload",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testBlog](db).Preload("Posts").Find()
+				return err
+			},
+			scoped: true,
+		},
+		{
+			name: "UnscopedPreload",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testBlog](db).Preload("Posts").Unscoped().Find()
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{}}}
+				db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+				if err := tt.run(db); err != nil && !strings.Contains(err.Error(), "not found") {
+					t.Fatal(err)
+				}
+				script := server.requests[0].script
+				if scoped := strings.Contains(script, ".where(__.has("); scoped != tt.scoped {
+					t.Errorf("Expected default scope %v, got %s", tt.scoped, script)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"ScopesApplyInOrder", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+			_, err := driver.Model[testPublishedPost](db).Scopes(titled("graphs"), limited).Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			request := server.requests[0]
+			if !slices.Contains(request.bindingValues(), any("graphs")) {
+				t.Errorf("Expected the title scope to be applied, got %v", request.bindingValues())
+			}
+			if !strings.Contains(request.script, ".limit(") {
+				t.Errorf("Expected the limit scope to be applied, got %s", request.script)
+			}
+		},
+	)
+}
@@ -0,0 +1,410 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"unsafe"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// scriptSubmitter submits a Gremlin script with its parameter bindings and
+// request arguments and returns every result. It is the seam used by script
+// mode so tests can replace the server with recorded responses.
+type scriptSubmitter interface {
+	submitScript(script string, bindings map[string]any, requestArgs map[string]any) ([]*gremlingo.Result, error)
+}
+
+// remoteScriptSubmitter submits scripts through the connection's underlying
+// gremlingo.Client with Client.SubmitWithOptions.
+type remoteScriptSubmitter struct {
+	remote *gremlingo.DriverRemoteConnection
+}
+
+func (s remoteScriptSubmitter) submitScript(
+	script string,
+	bindings map[string]any,
+	requestArgs map[string]any,
+) ([]*gremlingo.Result, error) {
+	builder := new(gremlingo.RequestOptionsBuilder).SetBindings(bindings)
+	if timeout, ok := intArg(requestArgs[evaluationTimeoutArg]); ok {
+		builder.SetEvaluationTimeout(timeout)
+	}
+	if batchSize, ok := intArg(requestArgs[batchSizeArg]); ok {
+		builder.SetBatchSize(batchSize)
+	}
+	resultSet, err := s.remote.SubmitWithOptions(script, builder.Create())
+	if err != nil {
+		return nil, err
+	}
+	return resultSet.All()
+}
+
+// Request arguments gremlingo lifts out of an OptionsStrategy when submitting
+// bytecode. Script translation does the same instead of rendering them as
+// with() steps.
+const (
+	evaluationTimeoutArg = "evaluationTimeout"
+	batchSizeArg         = "batchSize"
+)
+
+func intArg(value any) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+// scriptOptions controls dialect specific rewrites applied while
+// translating bytecode to a script.
+type scriptOptions struct {
+	// dropValueMapBy removes by() modulators following valueMap() for
+	// servers that reject them. Single-valued properties then arrive as
+	// one-element lists and are unfolded client side by setFieldFromValue.
+	dropValueMapBy bool
+}
+
+// scriptOptionsFor derives the translation options from a dialect.
+func scriptOptionsFor(dialect Dialect) scriptOptions {
+	return scriptOptions{dropValueMapBy: !dialect.Supports(FeatureValueMapBy)}
+}
+
+// translateScript renders a traversal as a Groovy Gremlin script rooted at
+// g. Every literal argument is lifted into a binding named p0, p1, ... so
+// values keep their serialized types and the script text stays cacheable
+// on the server. OptionsStrategy request arguments such as evaluationTimeout
+// are returned separately to be sent with the request.
+func translateScript(
+	traversal *gremlingo.GraphTraversal,
+	opts scriptOptions,
+) (string, map[string]any, map[string]any, error) {
+	if errBytecodeLayout != nil {
+		return "", nil, nil, errBytecodeLayout
+	}
+	translator := &scriptTranslator{
+		opts:        opts,
+		bindings:    make(map[string]any),
+		requestArgs: make(map[string]any),
+	}
+	var sb strings.Builder
+	sb.WriteString("g")
+	bytecode := reflect.ValueOf(traversal.Bytecode)
+	if err := translator.writeInstructions(&sb, readField(bytecode.Elem(), "sourceInstructions")); err != nil {
+		return "", nil, nil, err
+	}
+	if err := translator.writeInstructions(&sb, readField(bytecode.Elem(), "stepInstructions")); err != nil {
+		return "", nil, nil, err
+	}
+	return sb.String(), translator.bindings, translator.requestArgs, nil
+}
+
+type scriptTranslator struct {
+	opts        scriptOptions
+	bindings    map[string]any
+	requestArgs map[string]any
+}
+
+// bind records value as the next positional binding and returns its name.
+func (t *scriptTranslator) bind(value any) string {
+	name := fmt.Sprintf("p%d", len(t.bindings))
+	t.bindings[name] = value
+	return name
+}
+
+func (t *scriptTranslator) writeInstructions(sb *strings.Builder, instructions reflect.Value) error {
+	previous := ""
+	for i := range instructions.Len() {
+		instruction := instructions.Index(i)
+		operator := readField(instruction, "operator").String()
+		if t.opts.dropValueMapBy && operator == "by" && previous == "valueMap" {
+			continue
+		}
+		previous = operator
+		args, _ := readField(instruction, "arguments").Interface().([]any)
+		if operator == "withStrategies" {
+			if err := t.writeStrategies(sb, args); err != nil {
+				return err
+			}
+			continue
+		}
+		sb.WriteString(".")
+		sb.WriteString(operator)
+		if err := t.writeArgs(sb, args); err != nil {
+			return fmt.Errorf("script translation of %s(): %w", operator, err)
+		}
+	}
+	return nil
+}
+
+// writeStrategies renders withStrategies() arguments. OptionsStrategy has
+// no script constructor and is written as with() steps instead, except for
+// request arguments, which are collected in requestArgs.
+func (t *scriptTranslator) writeStrategies(sb *strings.Builder, args []any) error {
+	strategies := make([]string, 0, len(args))
+	for _, arg := range args {
+		strategy := reflect.ValueOf(arg)
+		if strategy.Kind() != reflect.Pointer || strategy.Elem().Type().Name() != "traversalStrategy" {
+			return fmt.Errorf("script translation of withStrategies(): unsupported argument %T", arg)
+		}
+		name := readField(strategy.Elem(), "name").String()
+		className := name[strings.LastIndex(name, ".")+1:]
+		configuration, _ := readField(strategy.Elem(), "configuration").Interface().(map[string]any)
+		keys := sortedKeys(configuration)
+		if className == "OptionsStrategy" {
+			for _, key := range keys {
+				if key == evaluationTimeoutArg || key == batchSizeArg {
+					t.requestArgs[key] = configuration[key]
+					continue
+				}
+				fmt.Fprintf(sb, ".with(%s, %s)", t.bind(key), t.bind(configuration[key]))
+			}
+			continue
+		}
+		params := make([]string, 0, len(keys))
+		for _, key := range keys {
+			value, err := t.arg(configuration[key])
+			if err != nil {
+				return err
+			}
+			params = append(params, key+": "+value)
+		}
+		strategies = append(strategies, fmt.Sprintf("new %s(%s)", className, strings.Join(params, ", ")))
+	}
+	if len(strategies) > 0 {
+		sb.WriteString(".withStrategies(")
+		sb.WriteString(strings.Join(strategies, ", "))
+		sb.WriteString(")")
+	}
+	return nil
+}
+
+func (t *scriptTranslator) writeArgs(sb *strings.Builder, args []any) error {
+	sb.WriteString("(")
+	for i, arg := range args {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		value, err := t.arg(arg)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(value)
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+// arg renders a single step argument.
+func (t *scriptTranslator) arg(arg any) (string, error) {
+	switch value := arg.(type) {
+	case nil:
+		return "null", nil
+	case *gremlingo.Bytecode:
+		var sb strings.Builder
+		sb.WriteString("__")
+		if err := t.writeInstructions(&sb, readField(reflect.ValueOf(value).Elem(), "stepInstructions")); err != nil {
+			return "", err
+		}
+		return sb.String(), nil
+	case *gremlingo.GraphTraversal:
+		return t.arg(value.Bytecode)
+	case *gremlingo.Binding:
+		t.bindings[value.Key] = value.Value
+		return value.Key, nil
+	case gremlingo.TextPredicate:
+		return t.predicate("TextP", reflect.ValueOf(value).Elem())
+	case gremlingo.Predicate:
+		return t.predicate("P", reflect.ValueOf(value).Elem())
+	case gremlingo.Set:
+		return t.arg(value.ToSlice())
+	}
+
+	rv := reflect.ValueOf(arg)
+	if rv.Kind() == reflect.String && rv.Type().PkgPath() == reflect.TypeFor[gremlingo.Bytecode]().PkgPath() {
+		// gremlingo enums (T, Order, Scope, Cardinality, ...) are string
+		// types whose values match the statically imported script names.
+		return rv.String(), nil
+	}
+	if rv.Kind() == reflect.Slice && !isScriptLiteral(rv) {
+		items := make([]string, rv.Len())
+		for i := range rv.Len() {
+			item, err := t.arg(rv.Index(i).Interface())
+			if err != nil {
+				return "", err
+			}
+			items[i] = item
+		}
+		return "[" + strings.Join(items, ", ") + "]", nil
+	}
+	if !isScriptLiteral(rv) {
+		return "", fmt.Errorf("unsupported argument %T", arg)
+	}
+	return t.bind(arg), nil
+}
+
+// predicate renders a P or TextP predicate. and/or combine two predicates;
+// within/without take their values as a single list binding.
+func (t *scriptTranslator) predicate(class string, predicate reflect.Value) (string, error) {
+	operator := readField(predicate, "operator").String()
+	values, _ := readField(predicate, "values").Interface().([]any)
+	switch operator {
+	case "and", "or":
+		if len(values) != 2 {
+			return "", fmt.Errorf("%s.%s expects two predicates", class, operator)
+		}
+		left, err := t.predicateOperand(class, values[0])
+		if err != nil {
+			return "", err
+		}
+		right, err := t.predicateOperand(class, values[1])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s.%s(%s)", left, operator, right), nil
+	case "within", "without":
+		if len(values) != 1 || reflect.ValueOf(values[0]).Kind() != reflect.Slice {
+			values = []any{values}
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(class)
+	sb.WriteString(".")
+	sb.WriteString(operator)
+	if err := t.writeArgs(&sb, values); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// predicateOperand renders an and/or operand, which gremlingo stores either
+// as a predicate pointer or, for TextP, as a predicate value.
+func (t *scriptTranslator) predicateOperand(class string, operand any) (string, error) {
+	rv := reflect.ValueOf(operand)
+	if rv.Kind() == reflect.Struct {
+		addressable := reflect.New(rv.Type())
+		addressable.Elem().Set(rv)
+		return t.predicate(class, addressable.Elem())
+	}
+	return t.arg(operand)
+}
+
+// isScriptLiteral reports whether rv can be sent as a binding value: it
+// contains no traversals, predicates, enums or strategies.
+func isScriptLiteral(rv reflect.Value) bool {
+	if !rv.IsValid() {
+		return true
+	}
+	switch rv.Kind() { //nolint:exhaustive // remaining kinds are plain values
+	case reflect.Interface, reflect.Pointer:
+		if rv.IsNil() {
+			return true
+		}
+		switch rv.Interface().(type) {
+		case *gremlingo.Bytecode, *gremlingo.GraphTraversal, *gremlingo.Binding,
+			gremlingo.Predicate, gremlingo.TextPredicate:
+			return false
+		}
+		if rv.Kind() == reflect.Pointer && rv.Elem().Kind() == reflect.Struct &&
+			rv.Elem().Type().Name() == "traversalStrategy" {
+			return false
+		}
+		return isScriptLiteral(rv.Elem())
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			if !isScriptLiteral(rv.Index(i)) {
+				return false
+			}
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			if !isScriptLiteral(iter.Key()) || !isScriptLiteral(iter.Value()) {
+				return false
+			}
+		}
+	case reflect.String:
+		return rv.Type().PkgPath() != reflect.TypeFor[gremlingo.Bytecode]().PkgPath()
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	}
+	return true
+}
+
+// readField returns a readable copy of the (possibly unexported) field name
+// of the addressable struct value. gremlingo exposes neither bytecode
+// instructions nor predicate operands, so script translation and views read
+// them directly. prepare and translateScript return
+// errBytecodeLayout before any field is read when the gremlingo types do not
+// have the fields bytecodeLayout expects.
+func readField(structValue reflect.Value, name string) reflect.Value {
+	field := structValue.FieldByName(name)
+	return reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem() //nolint:gosec // see doc comment
+}
+
+// bytecodeLayoutVersion is the gremlin-go version whose unexported bytecode
+// fields readField reads. go.mod pins it, and TestBytecodeLayout fails when
+// the module resolves to another version, so upgrades revisit readField.
+const bytecodeLayoutVersion = "v3.7.4"
+
+// errBytecodeLayout is non-nil when the gremlingo version in use changed the
+// unexported fields readField reads.
+var errBytecodeLayout = checkBytecodeLayout()
+
+// checkBytecodeLayout checks the fields readField reads against the layout
+// of gremlin-go bytecodeLayoutVersion, for builds that resolve another
+// version regardless of the pin.
+func checkBytecodeLayout() error {
+	bytecode := reflect.TypeFor[gremlingo.Bytecode]()
+	err := checkFields(bytecode, map[string]reflect.Kind{
+		"sourceInstructions": reflect.Slice,
+		"stepInstructions":   reflect.Slice,
+	})
+	if err != nil {
+		return err
+	}
+	steps, _ := bytecode.FieldByName("stepInstructions")
+	for rt, fields := range map[reflect.Type]map[string]reflect.Kind{
+		steps.Type.Elem(): {"operator": reflect.String, "arguments": reflect.Slice},
+		reflect.TypeOf(gremlingo.OptionsStrategy(nil)).Elem(): {
+			"name":          reflect.String,
+			"configuration": reflect.Map,
+		},
+		reflect.TypeOf(gremlingo.P.Eq(nil)).Elem():            {"operator": reflect.String, "values": reflect.Slice},
+		reflect.TypeOf(gremlingo.TextP.Containing("")).Elem(): {"operator": reflect.String, "values": reflect.Slice},
+	} {
+		if err = checkFields(rt, fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkFields returns an error when the struct type rt lacks one of fields
+// or holds it with another kind.
+func checkFields(rt reflect.Type, fields map[string]reflect.Kind) error {
+	if rt.Kind() != reflect.Struct {
+		return fmt.Errorf("unsupported gremlin-go version: %s is not a struct", rt)
+	}
+	for name, kind := range fields {
+		if field, ok := rt.FieldByName(name); !ok || field.Type.Kind() != kind {
+			return fmt.Errorf("unsupported gremlin-go version: %s has no %s field %q", rt, kind, name)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,244 @@
+package driver_test
+
+import (
+	"errors"
+	"reflect"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+func TestTranslateScript(t *testing.T) {
+	t.Parallel()
+	g := gremlingo.NewDefaultGraphTraversalSource()
+	__ := gremlingo.T__
+	tests := []struct {
+		name         string
+		traversal    *gremlingo.GraphTraversal
+		dialect      driver.Dialect
+		wantScript   string
+		wantBindings map[string]any
+	}{
+		{
+			name:         "literals become bindings",
+			traversal:    g.V("1").HasLabel("person").Has("name", "O'Brien").Has("age", int64(30)),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.V(p0).hasLabel(p1).has(p2, p3).has(p4, p5)",
+			wantBindings: map[string]any{"p0": "1", "p1": "person", "p2": "name", "p3": "O'Brien", "p4": "age", "p5": int64(30)},
+		},
+		{
+			name:         "enums are written as script names",
+			traversal:    g.AddV("person").Property(gremlingo.Cardinality.List, "tags", "a").Property(gremlingo.T.Id, "x"),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.addV(p0).property(list, p1, p2).property(id, p3)",
+			wantBindings: map[string]any{"p0": "person", "p1": "tags", "p2": "a", "p3": "x"},
+		},
+		{
+			name: "predicates",
+			traversal: g.V().
+				Has("age", gremlingo.P.Gt(1).And(gremlingo.P.Lt(9))).
+				Has("name", gremlingo.P.Within("a", "b")).
+				Has("bio", gremlingo.TextP.Containing("go")),
+			dialect:    driver.TinkerGraphDialect{},
+			wantScript: "g.V().has(p0, P.gt(p1).and(P.lt(p2))).has(p3, P.within(p4)).has(p5, TextP.containing(p6))",
+			wantBindings: map[string]any{
+				"p0": "age", "p1": 1, "p2": 9,
+				"p3": "name", "p4": []any{"a", "b"},
+				"p5": "bio", "p6": "go",
+			},
+		},
+		{
+			name:         "anonymous traversals and order",
+			traversal:    g.V().Where(__.Out("knows").Count().Is(2)).Order().By("age", gremlingo.Order.Desc),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.V().where(__.out(p0).count().is(p1)).order().by(p2, desc)",
+			wantBindings: map[string]any{"p0": "knows", "p1": 2, "p2": "age"},
+		},
+		{
+			name:         "source instructions",
+			traversal:    g.WithSack(0).With("tenant", "acme").V(),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.withSack(p0).with(p1, p2).V()",
+			wantBindings: map[string]any{"p0": int32(0), "p1": "tenant", "p2": "acme"},
+		},
+		{
+			name:         "request arguments are not rendered",
+			traversal:    g.With("evaluationTimeout", 500).V(),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.V()",
+			wantBindings: map[string]any{},
+		},
+		{
+			name:         "valueMap by kept when supported",
+			traversal:    g.V().ValueMap(true).By(__.Unfold()),
+			dialect:      driver.TinkerGraphDialect{},
+			wantScript:   "g.V().valueMap(p0).by(__.unfold())",
+			wantBindings: map[string]any{"p0": true},
+		},
+		{
+			name:         "valueMap by dropped for cosmos",
+			traversal:    g.V().Local(__.ValueMap(true).By(__.Unfold())).Order().By("name"),
+			dialect:      driver.CosmosDBDialect{},
+			wantScript:   "g.V().local(__.valueMap(p0)).order().by(p1)",
+			wantBindings: map[string]any{"p0": true, "p1": "name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				script, bindings, err := driver.TranslateScriptForTest(tt.traversal, tt.dialect)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if script != tt.wantScript {
+					t.Errorf("Expected script %s, got %s", tt.wantScript, script)
+				}
+				if !reflect.DeepEqual(bindings, tt.wantBindings) {
+					t.Errorf("Expected bindings %v, got %v", tt.wantBindings, bindings)
+				}
+			},
+		)
+	}
+}
+
+func TestScriptModeOffline(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"PreloadKeepsValueMapBy", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{ScriptMode: true}, server.submit)
+			if _, err := driver.Model[testPerson](db).Preload("Topics").Find(); err != nil {
+				t.Fatal(err)
+			}
+			script := server.requests[0].script
+			for _, want := range []string{"g.V().hasLabel(p0).local(__.union(", ".valueMap(", ").by(__.choose(", "__.out("} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected %q in script %s", want, script)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"TransactionsUnavailable", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{ScriptMode: true}, server.submit)
+			if _, err := db.Begin(); !errors.Is(err, driver.ErrTransactionUnsupported) {
+				t.Errorf("Expected ErrTransactionUnsupported in script mode, got %v", err)
+			}
+		},
+	)
+}
+
+func TestScriptModeMatchesBytecode(t *testing.T) {
+	bytecodeDB, err := driver.Open(DbURL, driver.Config{Dialect: dbDialect})
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptDB, err := driver.Open(DbURL, driver.Config{Dialect: dbDialect, ScriptMode: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanDB)
+
+	// Writes go through scripts, reads are compared across both modes.
+	person, topics := seedPreloadData(t, scriptDB)
+	if err = driver.Model[testTopic](scriptDB).
+		IDs(topics[0].ID).
+		Updates(map[string]any{"title": "graph theory"}); err != nil {
+		t.Fatal(err)
+	}
+
+	find := func(db *driver.GremlinDriver) []testPerson {
+		t.Helper()
+		people, findErr := driver.Model[testPerson](db).
+			Preload("Topics").
+			Where("name", comparator.IN, []string{person.Name, "nobody"}).
+			OrderBy("name", driver.Asc).
+			Find()
+		if findErr != nil {
+			t.Fatal(findErr)
+		}
+		return people
+	}
+	bytecodePeople := find(bytecodeDB)
+	scriptPeople := find(scriptDB)
+	if !reflect.DeepEqual(bytecodePeople, scriptPeople) {
+		t.Errorf("Expected identical results, bytecode %+v, script %+v", bytecodePeople, scriptPeople)
+	}
+	if len(scriptPeople) != 1 || len(scriptPeople[0].Topics) != len(topics) {
+		t.Fatalf("Unexpected script mode results %+v", scriptPeople)
+	}
+
+	bytecodeCount, err := driver.Model[testTopic](bytecodeDB).Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scriptCount, err := driver.Model[testTopic](scriptDB).Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytecodeCount != scriptCount {
+		t.Errorf("Expected count %d, got %d", bytecodeCount, scriptCount)
+	}
+
+	updated, err := driver.Model[testTopic](bytecodeDB).ID(topics[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Title != "graph theory" {
+		t.Errorf("Expected script mode update to be visible, got %s", updated.Title)
+	}
+}
+
+func TestBytecodeLayout(t *testing.T) {
+	t.Parallel()
+	// Fails when a gremlin-go upgrade changes the unexported fields the
+	// driver reads from bytecode; see readField.
+	if err := driver.BytecodeLayoutErrorForTest(); err != nil {
+		t.Fatal(err)
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Fatal("Expected build info")
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/apache/tinkerpop/gremlin-go/v3" && dep.Version != driver.BytecodeLayoutVersionForTest {
+			t.Fatalf(
+				"gremlin-go %s is in use but readField reads the bytecode layout of %s; check it and update the pin",
+				dep.Version, driver.BytecodeLayoutVersionForTest,
+			)
+		}
+	}
+
+	type instruction struct {
+		operator string
+		args     []any
+	}
+	tests := []struct {
+		name   string
+		fields map[string]reflect.Kind
+	}{
+		{name: "MissingField", fields: map[string]reflect.Kind{"arguments": reflect.Slice}},
+		{name: "OtherKind", fields: map[string]reflect.Kind{"operator": reflect.Slice}},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				err := driver.CheckFieldsForTest(reflect.TypeFor[instruction](), tt.fields)
+				if err == nil || !strings.Contains(err.Error(), "unsupported gremlin-go version") {
+					t.Errorf("Expected an unsupported gremlin-go version error, got %v", err)
+				}
+			},
+		)
+	}
+}
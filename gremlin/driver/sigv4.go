@@ -0,0 +1,273 @@
+package driver
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+const (
+	// NeptuneIAMService is the SigV4 service name of Neptune's data plane.
+	NeptuneIAMService = "neptune-db"
+
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// AWSCredentials are the AWS credentials used to sign requests.
+// SessionToken is only set for temporary credentials.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSCredentialsProvider supplies AWS credentials. Retrieve is called every
+// time a connection is opened so rotated credentials are picked up on
+// reconnect. Wrap an aws-sdk-go-v2 aws.CredentialsProvider with
+// AWSCredentialsProviderFunc to reuse the SDK's credential chain.
+type AWSCredentialsProvider interface {
+	Retrieve(ctx context.Context) (AWSCredentials, error)
+}
+
+// AWSCredentialsProviderFunc adapts a function to AWSCredentialsProvider.
+type AWSCredentialsProviderFunc func(ctx context.Context) (AWSCredentials, error)
+
+func (f AWSCredentialsProviderFunc) Retrieve(ctx context.Context) (AWSCredentials, error) {
+	return f(ctx)
+}
+
+// StaticAWSCredentials always returns the same credentials.
+type StaticAWSCredentials AWSCredentials
+
+func (c StaticAWSCredentials) Retrieve(context.Context) (AWSCredentials, error) {
+	return AWSCredentials(c), nil
+}
+
+// EnvAWSCredentials reads AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN from the environment.
+type EnvAWSCredentials struct{}
+
+func (EnvAWSCredentials) Retrieve(context.Context) (AWSCredentials, error) {
+	credentials := AWSCredentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return AWSCredentials{}, errors.New("AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY must be set")
+	}
+	return credentials, nil
+}
+
+// IAMAuthConfig enables SigV4 signing of the websocket handshake for
+// IAM-enabled Neptune clusters.
+type IAMAuthConfig struct {
+	// Region is the AWS region of the cluster, e.g. "us-east-1".
+	Region string
+	// Credentials supplies the signing credentials (default EnvAWSCredentials).
+	Credentials AWSCredentialsProvider
+	// Service overrides the SigV4 service name (default NeptuneIAMService).
+	Service string
+}
+
+// iamSigner signs websocket handshakes for an IAM-enabled endpoint.
+type iamSigner struct {
+	url    *url.URL
+	config IAMAuthConfig
+	now    func() time.Time
+}
+
+// newIAMSigner validates config and applies its defaults.
+func newIAMSigner(endpoint string, config IAMAuthConfig) (*iamSigner, error) {
+	if config.Region == "" {
+		return nil, errors.New("IAMAuth: region is required")
+	}
+	if config.Credentials == nil {
+		config.Credentials = EnvAWSCredentials{}
+	}
+	if config.Service == "" {
+		config.Service = NeptuneIAMService
+	}
+	signURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("IAMAuth: %w", err)
+	}
+	// The handshake is signed as the HTTP request it upgrades from.
+	switch signURL.Scheme {
+	case "ws":
+		signURL.Scheme = "http"
+	case "wss":
+		signURL.Scheme = "https"
+	}
+	return &iamSigner{url: signURL, config: config, now: time.Now}, nil
+}
+
+// header signs a GET request for the endpoint and returns the headers to
+// send with the websocket handshake.
+func (s *iamSigner) header() (http.Header, error) {
+	credentials, err := s.config.Credentials.Retrieve(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(http.MethodGet, s.url.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	signSigV4(req, nil, credentials, s.config.Region, s.config.Service, s.now())
+	return req.Header, nil
+}
+
+// authInfo returns a gremlingo auth provider that signs a fresh handshake
+// for every connection the driver opens, including reconnects.
+func (s *iamSigner) authInfo(logError func(format string, args ...any)) gremlingo.AuthInfoProvider {
+	return gremlingo.NewDynamicAuth(
+		func() gremlingo.AuthInfoProvider {
+			header, err := s.header()
+			if err != nil {
+				logError("IAMAuth: failed to sign request: %v", err)
+				return &gremlingo.AuthInfo{}
+			}
+			return gremlingo.HeaderAuthInfo(header)
+		},
+	)
+}
+
+// signSigV4 adds the X-Amz-Date, X-Amz-Security-Token (for temporary
+// credentials) and Authorization headers for AWS Signature Version 4. Every
+// header already on req, plus host, is signed.
+func signSigV4(
+	req *http.Request,
+	body []byte,
+	credentials AWSCredentials,
+	region string,
+	service string,
+	now time.Time,
+) {
+	now = now.UTC()
+	amzDate := now.Format(sigV4TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := sigV4CanonicalHeaders(req)
+	canonicalRequest := strings.Join(
+		[]string{
+			req.Method,
+			sigV4CanonicalURI(req.URL),
+			sigV4CanonicalQuery(req.URL),
+			canonicalHeaders,
+			signedHeaders,
+			sha256Hex(body),
+		}, "\n",
+	)
+
+	scope := strings.Join([]string{now.Format(sigV4DateFormat), region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join(
+		[]string{sigV4Algorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n",
+	)
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), now.Format(sigV4DateFormat))
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set(
+		"Authorization", fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			sigV4Algorithm, credentials.AccessKeyID, scope, signedHeaders, signature,
+		),
+	)
+}
+
+// sigV4CanonicalHeaders returns the canonical header block (each line
+// newline terminated) and the semicolon separated signed header names.
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	values := map[string][]string{"host": {req.Host}}
+	if req.Host == "" {
+		values["host"] = []string{req.URL.Host}
+	}
+	for name, headerValues := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "authorization" {
+			continue
+		}
+		for _, value := range headerValues {
+			values[lower] = append(values[lower], strings.Join(strings.Fields(value), " "))
+		}
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteString(":")
+		sb.WriteString(strings.Join(values[name], ","))
+		sb.WriteString("\n")
+	}
+	return sb.String(), strings.Join(names, ";")
+}
+
+func sigV4CanonicalURI(u *url.URL) string {
+	path := u.EscapedPath()
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// sigV4CanonicalQuery sorts query parameters by name, then value, and
+// encodes them per RFC 3986.
+func sigV4CanonicalQuery(u *url.URL) string {
+	query := u.Query()
+	pairs := make([][2]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, [2]string{sigV4Escape(name), sigV4Escape(value)})
+		}
+	}
+	slices.SortFunc(pairs, func(a, b [2]string) int {
+		if c := strings.Compare(a[0], b[0]); c != 0 {
+			return c
+		}
+		return strings.Compare(a[1], b[1])
+	})
+	encoded := make([]string, len(pairs))
+	for i, pair := range pairs {
+		encoded[i] = pair[0] + "=" + pair[1]
+	}
+	return strings.Join(encoded, "&")
+}
+
+func sigV4Escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,134 @@
+package driver_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+// Credentials and timestamp shared by AWS's published SigV4 test suite.
+var (
+	sigV4TestCredentials = driver.AWSCredentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+	}
+	sigV4TestTime = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+)
+
+func TestSignSigV4(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		method        string
+		url           string
+		header        map[string]string
+		service       string
+		wantAuthority string
+	}{
+		{
+			name:    "get-vanilla",
+			method:  http.MethodGet,
+			url:     "https://example.amazonaws.com/",
+			service: "service",
+			wantAuthority: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:    "post-vanilla",
+			method:  http.MethodPost,
+			url:     "https://example.amazonaws.com/",
+			service: "service",
+			wantAuthority: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:    "get-vanilla-query-order-key-case",
+			method:  http.MethodGet,
+			url:     "https://example.amazonaws.com/?Param2=value2&Param1=value1",
+			service: "service",
+			wantAuthority: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+				"SignedHeaders=host;x-amz-date, " +
+				"Signature=b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:    "iam-list-users",
+			method:  http.MethodGet,
+			url:     "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08",
+			header:  map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			service: "iam",
+			wantAuthority: "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, " +
+				"SignedHeaders=content-type;host;x-amz-date, " +
+				"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				req, err := http.NewRequest(tt.method, tt.url, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				for key, value := range tt.header {
+					req.Header.Set(key, value)
+				}
+				driver.SignSigV4ForTest(req, nil, sigV4TestCredentials, "us-east-1", tt.service, sigV4TestTime)
+				if got := req.Header.Get("Authorization"); got != tt.wantAuthority {
+					t.Errorf("Expected Authorization\n%s\ngot\n%s", tt.wantAuthority, got)
+				}
+				if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+					t.Errorf("Expected X-Amz-Date 20150830T123600Z, got %s", got)
+				}
+			},
+		)
+	}
+}
+
+func TestIAMHandshakeHeader(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"SignsNeptuneHandshake", func(t *testing.T) {
+			t.Parallel()
+			credentials := sigV4TestCredentials
+			credentials.SessionToken = "session-token"
+			header, err := driver.IAMHandshakeHeaderForTest(
+				"wss://cluster.us-east-1.neptune.amazonaws.com:8182/gremlin",
+				driver.IAMAuthConfig{Region: "us-east-1", Credentials: driver.StaticAWSCredentials(credentials)},
+				sigV4TestTime,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authorization := header.Get("Authorization")
+			if !strings.Contains(authorization, "/20150830/us-east-1/neptune-db/aws4_request") {
+				t.Errorf("Expected neptune-db credential scope, got %s", authorization)
+			}
+			if !strings.Contains(authorization, "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+				t.Errorf("Expected session token to be signed, got %s", authorization)
+			}
+			if header.Get("X-Amz-Security-Token") != "session-token" {
+				t.Errorf("Expected session token header, got %v", header)
+			}
+		},
+	)
+
+	t.Run(
+		"RequiresRegion", func(t *testing.T) {
+			t.Parallel()
+			_, err := driver.IAMHandshakeHeaderForTest(
+				"wss://cluster:8182/gremlin",
+				driver.IAMAuthConfig{Credentials: driver.StaticAWSCredentials(sigV4TestCredentials)},
+				sigV4TestTime,
+			)
+			if err == nil {
+				t.Error("Expected missing region error")
+			}
+		},
+	)
+}
@@ -0,0 +1,94 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+var softDeleteType = reflect.TypeFor[gsmtypes.SoftDeleteType]()
+
+// typeImplementsSoftDelete reports whether rt is a struct whose pointer
+// implements gsmtypes.SoftDeleteType, typically by embedding
+// gsmtypes.SoftDelete.
+func typeImplementsSoftDelete(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && reflect.PointerTo(rt).Implements(softDeleteType)
+}
+
+// OnlyDeleted restricts the query to soft-deleted vertices.
+func (q *Query[T]) OnlyDeleted() *Query[T] {
+	if err := q.requireSoftDelete("OnlyDeleted"); err != nil {
+		q.err = err
+		return q
+	}
+	q.writeDebugString(".OnlyDeleted()")
+	q.onlyDeleted = true
+	return q
+}
+
+// Restore clears deleted_at on the soft-deleted vertices matching the query.
+func (q *Query[T]) Restore() error {
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.requireSoftDelete("Restore"); err != nil {
+		return err
+	}
+	if err := q.requireConditions("Restore"); err != nil {
+		return err
+	}
+	q.onlyDeleted = true
+	q.writeDebugString(".Restore().Iterate()")
+	return q.audited(
+		AuditUpdate, []string{gsmtypes.DeletedAt}, nil,
+		func(db *GremlinDriver, query *gremlingo.GraphTraversal) error {
+			query = query.SideEffect(anonymousTraversal.Properties(gsmtypes.DeletedAt).Drop())
+			return db.iterate(q.touch(query, q.db.timestamps.now()))
+		},
+	)
+}
+
+// HardDelete drops the matching vertices, even for models that soft
+// delete. Soft-deleted vertices only match after Unscoped or OnlyDeleted.
+func (q *Query[T]) HardDelete() error {
+	if q.err != nil {
+		return q.err
+	}
+	if err := q.requireConditions("HardDelete"); err != nil {
+		return err
+	}
+	q.writeDebugString(".Drop().Iterate()")
+	return q.audited(
+		AuditDelete, nil, nil, func(db *GremlinDriver, query *gremlingo.GraphTraversal) error {
+			return db.iterate(query.Drop())
+		},
+	)
+}
+
+// softDeletes reports whether T soft deletes.
+func (q *Query[T]) softDeletes() bool {
+	return schemaFor(reflect.TypeFor[T]()).softDelete
+}
+
+func (q *Query[T]) requireSoftDelete(operation string) error {
+	if !q.softDeletes() {
+		return fmt.Errorf("%s: %s does not embed gsmtypes.SoftDelete", operation, reflect.TypeFor[T]())
+	}
+	return nil
+}
+
+// addSoftDeleteFilter hides soft-deleted vertices unless the query is
+// Unscoped, and keeps only them after OnlyDeleted.
+func (q *Query[T]) addSoftDeleteFilter(query *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+	switch {
+	case !q.softDeletes():
+		return query
+	case q.onlyDeleted:
+		return query.Has(gsmtypes.DeletedAt)
+	case q.unscoped:
+		return query
+	}
+	return query.HasNot(gsmtypes.DeletedAt)
+}
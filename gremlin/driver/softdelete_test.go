@@ -0,0 +1,169 @@
+package driver_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testArchivedPost struct {
+	gsmtypes.Vertex
+	gsmtypes.SoftDelete
+	Title string `json:"title" gremlin:"title"`
+}
+
+type testArchive struct {
+	gsmtypes.Vertex
+	Name  string             `json:"name"  gremlin:"name"`
+	Posts []testArchivedPost `json:"posts" gremlinEdge:"contains"`
+}
+
+func archivedPosts(db *driver.GremlinDriver) *driver.Query[testArchivedPost] {
+	return driver.Model[testArchivedPost](db).Where("title", comparator.EQ, "graphs")
+}
+
+func TestSoftDelete(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		run  func(db *driver.GremlinDriver) error
+		// want and notWant are script fragments
+		want    []string
+		notWant []string
+		// unbound is set when deleted_at is not part of the script
+		unbound bool
+	}{
+		{
+			name: "FindHidesDeleted",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := archivedPosts(db).Find()
+				return err
+			},
+			want: []string{".hasNot("},
+		},
+		{
+			name: "IDHidesDeleted",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testArchivedPost](db).ID("1")
+				return err
+			},
+			want: []string{".hasNot("},
+		},
+		{
+			name: "Unscoped",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := archivedPosts(db).Unscoped().Find()
+				return err
+			},
+			notWant: []string{".hasNot("},
+		},
+		{
+			name: "OnlyDeleted",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := archivedPosts(db).OnlyDeleted().Find()
+				return err
+			},
+			notWant: []string{".hasNot("},
+		},
+		{
+			name:    "DeleteSetsDeletedAt",
+			run:     func(db *driver.GremlinDriver) error { return archivedPosts(db).Delete() },
+			want:    []string{".hasNot(", ".property(single,"},
+			notWant: []string{".drop()"},
+		},
+		{
+			name:    "HardDelete",
+			run:     func(db *driver.GremlinDriver) error { return archivedPosts(db).Unscoped().HardDelete() },
+			want:    []string{".drop()"},
+			notWant: []string{".hasNot(", ".property("},
+			unbound: true,
+		},
+		{
+			name:    "Restore",
+			run:     func(db *driver.GremlinDriver) error { return archivedPosts(db).Restore() },
+			want:    []string{".sideEffect(__.properties(", ").drop())"},
+			notWant: []string{".hasNot("},
+		},
+		{
+			name: "PreloadHidesDeleted",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testArchive](db).Preload("Posts").Find()
+				return err
+			},
+			want: []string{".hasNot("},
+		},
+		{
+			name: "UnscopedPreload",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testArchive](db).Preload("Posts").Unscoped().Find()
+				return err
+			},
+			notWant: []string{".hasNot("},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{}}}
+				db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+				if err := tt.run(db); err != nil && !strings.Contains(err.Error(), "not found") {
+					t.Fatal(err)
+				}
+				request := server.requests[0]
+				for _, want := range tt.want {
+					if !strings.Contains(request.script, want) {
+						t.Errorf("Expected script to contain %s, got %s", want, request.script)
+					}
+				}
+				for _, notWant := range tt.notWant {
+					if strings.Contains(request.script, notWant) {
+						t.Errorf("Expected script not to contain %s, got %s", notWant, request.script)
+					}
+				}
+				if !tt.unbound && !slices.Contains(request.bindingValues(), any(gsmtypes.DeletedAt)) {
+					t.Errorf("Expected a deleted_at binding, got %v", request.bindingValues())
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"LoadsDeletedAt", func(t *testing.T) {
+			t.Parallel()
+			deletedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+			server := &recordedServer{
+				responses: [][]any{
+					{map[any]any{"id": "1", "title": "graphs", "deleted_at": deletedAt}},
+				},
+			}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+			posts, err := archivedPosts(db).OnlyDeleted().Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(posts) != 1 || !posts[0].IsDeleted() || !posts[0].GetDeletedAt().Equal(deletedAt) {
+				t.Errorf("Expected a deleted post, got %+v", posts)
+			}
+		},
+	)
+
+	t.Run(
+		"RequiresSoftDeleteModel", func(t *testing.T) {
+			t.Parallel()
+			db := driver.NewScriptDriverForTest(driver.Config{}, (&recordedServer{}).submit)
+			if _, err := driver.Model[testTopic](db).OnlyDeleted().Find(); err == nil {
+				t.Error("Expected OnlyDeleted to fail for a model without SoftDelete")
+			}
+			err := driver.Model[testTopic](db).Where("title", comparator.EQ, "graphs").Restore()
+			if err == nil {
+				t.Error("Expected Restore to fail for a model without SoftDelete")
+			}
+		},
+	)
+}
@@ -0,0 +1,221 @@
+package driver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// SubgraphVertex is a vertex of a SubgraphResult.
+type SubgraphVertex struct {
+	ID    any
+	Label string
+	// Value is a pointer to the model registered for Label with
+	// RegisterModel, or the vertex properties as a map[string]any.
+	Value any
+	// Properties holds the vertex properties, excluding id and label, with
+	// single values unwrapped and timestamps under their gsmtypes.Vertex names.
+	Properties map[string]any
+}
+
+// SubgraphResult is a set of vertices and the edges between them.
+type SubgraphResult struct {
+	Vertices []SubgraphVertex
+	Edges    []*PathEdge
+}
+
+// subgraphSeenKey is the side effect holding the vertices Subgraph has
+// already reached.
+const subgraphSeenKey = "subgraph_seen"
+
+// Subgraph returns the vertices with the given ids plus every vertex
+// reachable within depth hops over edges in either direction, together with
+// all edges between the returned vertices. edgeLabels restricts the followed
+// and returned edges; all edges are used when it is empty.
+//
+// The neighbourhood is collected with repeat(both()).emit(), visiting each
+// vertex once, and the edges
+// with bothE() rather than the subgraph() step, whose Graph result cannot be
+// deserialized by gremlin-go. Keep depth small on dense graphs.
+func Subgraph(db *GremlinDriver, ids []any, depth int, edgeLabels ...string) (*SubgraphResult, error) {
+	if len(ids) == 0 {
+		return nil, errors.New("subgraph: at least one vertex id is required")
+	}
+	labels := SliceToAnySlice(edgeLabels)
+
+	vertexQuery := db.g.V(ids...)
+	if depth > 0 {
+		// Each vertex is expanded once: the repeat drops neighbours already
+		// seen, so the work is bounded by the subgraph size rather than
+		// growing with the number of walks on dense or cyclic graphs.
+		vertexQuery = vertexQuery.Aggregate(subgraphSeenKey).Emit().Repeat(
+			anonymousTraversal.Both(labels...).
+				Dedup().
+				Where(P.Without(subgraphSeenKey)).
+				Aggregate(subgraphSeenKey),
+		).Times(depth)
+	}
+	vertexResults, err := db.toList(
+		vertexQuery.Dedup().ValueMap(true).By(unfoldSingleValueTraversal()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	subgraph := &SubgraphResult{Vertices: make([]SubgraphVertex, 0, len(vertexResults))}
+	vertexIDs := make([]any, 0, len(vertexResults))
+	for _, result := range vertexResults {
+		vertex, vertexErr := subgraphVertexFromResult(db, result)
+		if vertexErr != nil {
+			return nil, vertexErr
+		}
+		vertexIDs = append(vertexIDs, vertex.ID)
+		subgraph.Vertices = append(subgraph.Vertices, vertex)
+	}
+	if len(vertexIDs) == 0 {
+		return subgraph, nil
+	}
+
+	edgeResults, err := db.toList(
+		db.g.V(vertexIDs...).
+			BothE(labels...).
+			Where(anonymousTraversal.OtherV().HasId(vertexIDs...)).
+			Dedup().
+			ElementMap(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	subgraph.Edges = make([]*PathEdge, 0, len(edgeResults))
+	for _, result := range edgeResults {
+		elementMap, ok := result.GetInterface().(map[any]any)
+		if !ok {
+			return nil, errors.New("subgraph: edge result is not a map")
+		}
+		subgraph.Edges = append(subgraph.Edges, pathEdgeFromMap(elementMap))
+	}
+	return subgraph, nil
+}
+
+func subgraphVertexFromResult(db *GremlinDriver, result *gremlingo.Result) (SubgraphVertex, error) {
+	var vertex SubgraphVertex
+	mapResult, ok := result.GetInterface().(map[any]any)
+	if !ok {
+		return vertex, errors.New("subgraph: vertex result is not a map")
+	}
+	canonical, ok := db.timestamps.canonical(mapResult).(map[any]any)
+	if !ok {
+		return vertex, errors.New("subgraph: vertex result is not a map")
+	}
+	if err := unloadPolymorphic(&vertex.Value, canonical); err != nil {
+		return vertex, fmt.Errorf("subgraph: %w", err)
+	}
+	vertex.Properties = make(map[string]any, len(canonical))
+	for key, value := range canonical {
+		keyStr, _ := key.(string)
+		switch keyStr {
+		case "id":
+			vertex.ID = value
+		case "label":
+			vertex.Label, _ = value.(string)
+		default:
+			vertex.Properties[keyStr] = singleValue(value)
+		}
+	}
+	return vertex, nil
+}
+
+// cytoscapeElement is a node or edge in the Cytoscape.js elements JSON format.
+type cytoscapeElement struct {
+	Data map[string]any `json:"data"`
+}
+
+// CytoscapeJSON serializes the subgraph in the Cytoscape.js elements format:
+//
+//	{"elements": {"nodes": [{"data": {"id": "1", "label": "person", ...}}],
+//	              "edges": [{"data": {"id": "7", "source": "1", "target": "2", ...}}]}}
+//
+// Element ids are stringified and properties are copied into data.
+func (s *SubgraphResult) CytoscapeJSON() ([]byte, error) {
+	nodes := make([]cytoscapeElement, 0, len(s.Vertices))
+	for _, vertex := range s.Vertices {
+		data := make(map[string]any, len(vertex.Properties)+2)
+		for key, value := range vertex.Properties {
+			data[key] = value
+		}
+		data["id"] = fmt.Sprint(vertex.ID)
+		data["label"] = vertex.Label
+		nodes = append(nodes, cytoscapeElement{Data: data})
+	}
+	edges := make([]cytoscapeElement, 0, len(s.Edges))
+	for _, edge := range s.Edges {
+		data := make(map[string]any, len(edge.Properties)+4)
+		for key, value := range edge.Properties {
+			data[key] = value
+		}
+		data["id"] = fmt.Sprint(edge.ID)
+		data["label"] = edge.Label
+		data["source"] = fmt.Sprint(edge.OutV)
+		data["target"] = fmt.Sprint(edge.InV)
+		edges = append(edges, cytoscapeElement{Data: data})
+	}
+	return json.Marshal(map[string]any{
+		"elements": map[string]any{
+			"nodes": nodes,
+			"edges": edges,
+		},
+	})
+}
+
+// DOT serializes the subgraph as a Graphviz digraph. Vertices are labelled
+// with their label and properties, edges with their label.
+func (s *SubgraphResult) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph G {\n")
+	for _, vertex := range s.Vertices {
+		lines := append([]string{vertex.Label}, propertyLines(vertex.Properties)...)
+		for i, line := range lines {
+			lines[i] = dotEscape(line)
+		}
+		fmt.Fprintf(
+			&sb,
+			"  \"%s\" [label=\"%s\"];\n",
+			dotEscape(fmt.Sprint(vertex.ID)),
+			strings.Join(lines, `\n`),
+		)
+	}
+	for _, edge := range s.Edges {
+		fmt.Fprintf(
+			&sb,
+			"  \"%s\" -> \"%s\" [label=\"%s\"];\n",
+			dotEscape(fmt.Sprint(edge.OutV)),
+			dotEscape(fmt.Sprint(edge.InV)),
+			dotEscape(edge.Label),
+		)
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// dotReplacer escapes the characters that are special inside DOT quoted
+// strings. Newlines become Graphviz line breaks.
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotEscape escapes s for use inside a DOT quoted string.
+func dotEscape(s string) string {
+	return dotReplacer.Replace(s)
+}
+
+// propertyLines renders properties as sorted key=value lines.
+func propertyLines(properties map[string]any) []string {
+	lines := make([]string, 0, len(properties))
+	for key, value := range properties {
+		lines = append(lines, fmt.Sprintf("%s=%v", key, value))
+	}
+	slices.Sort(lines)
+	return lines
+}
@@ -0,0 +1,275 @@
+package driver_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+func testSubgraphResult() *driver.SubgraphResult {
+	return &driver.SubgraphResult{
+		Vertices: []driver.SubgraphVertex{
+			{ID: int64(1), Label: "person", Properties: map[string]any{"name": "alice", "age": 30}},
+			{ID: int64(2), Label: "person", Properties: map[string]any{"name": `bob "the builder"`}},
+		},
+		Edges: []*driver.PathEdge{
+			{ID: "e1", Label: "knows", OutV: int64(1), InV: int64(2), Properties: map[string]any{"since": 2020}},
+		},
+	}
+}
+
+func TestSubgraphCytoscapeJSON(t *testing.T) {
+	t.Parallel()
+	data, err := testSubgraphResult().CytoscapeJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Elements struct {
+			Nodes []struct {
+				Data map[string]any `json:"data"`
+			} `json:"nodes"`
+			Edges []struct {
+				Data map[string]any `json:"data"`
+			} `json:"edges"`
+		} `json:"elements"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Elements.Nodes) != 2 || len(decoded.Elements.Edges) != 1 {
+		t.Fatalf("Expected 2 nodes and 1 edge, got %s", data)
+	}
+	node := decoded.Elements.Nodes[0].Data
+	if node["id"] != "1" || node["label"] != "person" || node["name"] != "alice" {
+		t.Errorf("Unexpected node data %+v", node)
+	}
+	edge := decoded.Elements.Edges[0].Data
+	if edge["id"] != "e1" || edge["source"] != "1" || edge["target"] != "2" ||
+		edge["label"] != "knows" || edge["since"] != float64(2020) {
+		t.Errorf("Unexpected edge data %+v", edge)
+	}
+}
+
+func TestSubgraphDOT(t *testing.T) {
+	t.Parallel()
+	result := testSubgraphResult()
+	result.Vertices = append(
+		result.Vertices,
+		driver.SubgraphVertex{ID: "C:\\tmp", Label: "dir", Properties: map[string]any{"note": "two\nlines"}},
+	)
+	result.Edges = append(result.Edges, &driver.PathEdge{Label: `"owns"`, OutV: int64(2), InV: "C:\\tmp"})
+	nodes, edges := parseDOT(t, result.DOT())
+	wantNodes := map[string]string{
+		"1":       "person\nage=30\nname=alice",
+		"2":       "person\nname=bob \"the builder\"",
+		"C:\\tmp": "dir\nnote=two\nlines",
+	}
+	if !reflect.DeepEqual(nodes, wantNodes) {
+		t.Errorf("Expected nodes %q, got %q", wantNodes, nodes)
+	}
+	wantEdges := [][3]string{{"1", "2", "knows"}, {"2", "C:\\tmp", `"owns"`}}
+	if !reflect.DeepEqual(edges, wantEdges) {
+		t.Errorf("Expected edges %q, got %q", wantEdges, edges)
+	}
+}
+
+// parseDOT parses the subset of DOT written by SubgraphResult.DOT: a
+// digraph of node and edge statements with a label attribute. It returns
+// the labels by node ID and the edges as from, to and label, with the
+// escapes Graphviz applies when displaying them decoded.
+func parseDOT(t *testing.T, dot string) (map[string]string, [][3]string) {
+	t.Helper()
+	tokens := lexDOT(t, dot)
+	next := func() dotToken {
+		if len(tokens) == 0 {
+			t.Fatalf("Unexpected end of DOT input:\n%s", dot)
+		}
+		token := tokens[0]
+		tokens = tokens[1:]
+		return token
+	}
+	expect := func(want string) {
+		if token := next(); token.quoted || token.text != want {
+			t.Fatalf("Expected %q, got %q in DOT input:\n%s", want, token.text, dot)
+		}
+	}
+	id := func() string {
+		token := next()
+		if !token.quoted && !dotIDPattern.MatchString(token.text) || !token.quoted && dotKeywords[strings.ToLower(token.text)] {
+			t.Fatalf("Expected an ID, got %q in DOT input:\n%s", token.text, dot)
+		}
+		return dotEscapes.Replace(token.text)
+	}
+	label := func() string {
+		expect("[")
+		expect("label")
+		expect("=")
+		value := id()
+		expect("]")
+		expect(";")
+		return value
+	}
+	expect("digraph")
+	id()
+	expect("{")
+	nodes := map[string]string{}
+	var edges [][3]string
+	for len(tokens) > 0 && (tokens[0].quoted || tokens[0].text != "}") {
+		from := id()
+		if !tokens[0].quoted && tokens[0].text == "->" {
+			next()
+			to := id()
+			edges = append(edges, [3]string{from, to, label()})
+			continue
+		}
+		nodes[from] = label()
+	}
+	expect("}")
+	if len(tokens) > 0 {
+		t.Fatalf("Unexpected %q after the graph in DOT input:\n%s", tokens[0].text, dot)
+	}
+	return nodes, edges
+}
+
+var (
+	dotIDPattern = regexp.MustCompile(`^([A-Za-z_][A-Za-z_0-9]*|-?(\.[0-9]+|[0-9]+(\.[0-9]*)?))$`)
+	dotKeywords  = map[string]bool{
+		"node": true, "edge": true, "graph": true, "digraph": true, "subgraph": true, "strict": true,
+	}
+	// dotEscapes decodes the escapes Graphviz applies when it displays
+	// labels and node names.
+	dotEscapes = strings.NewReplacer(`\\`, `\`, `\n`, "\n")
+)
+
+type dotToken struct {
+	text   string
+	quoted bool
+}
+
+// lexDOT splits dot into tokens. Inside quoted strings only \" is an escape
+// to DOT itself; other backslash sequences are kept for the label decoding.
+func lexDOT(t *testing.T, dot string) []dotToken {
+	t.Helper()
+	var tokens []dotToken
+	for i := 0; i < len(dot); {
+		switch c := dot[i]; {
+		case c == ' ' || c == '\n' || c == '\t':
+			i++
+		case c == '"':
+			var sb strings.Builder
+			for i++; ; i++ {
+				if i >= len(dot) {
+					t.Fatalf("Unterminated string in DOT input:\n%s", dot)
+				}
+				if dot[i] == '"' {
+					i++
+					break
+				}
+				if dot[i] == '\\' && i+1 < len(dot) {
+					if dot[i+1] != '"' {
+						sb.WriteByte('\\')
+					}
+					i++
+				}
+				sb.WriteByte(dot[i])
+			}
+			tokens = append(tokens, dotToken{text: sb.String(), quoted: true})
+		case strings.HasPrefix(dot[i:], "->"):
+			tokens = append(tokens, dotToken{text: "->"})
+			i += 2
+		case strings.ContainsRune("{}[]=;", rune(c)):
+			tokens = append(tokens, dotToken{text: string(c)})
+			i++
+		default:
+			start := i
+			for i < len(dot) && !strings.ContainsRune(" \n\t\"{}[]=;-", rune(dot[i])) {
+				i++
+			}
+			if i == start {
+				t.Fatalf("Unexpected %q in DOT input:\n%s", c, dot)
+			}
+			tokens = append(tokens, dotToken{text: dot[start:i]})
+		}
+	}
+	return tokens
+}
+
+func TestSubgraphQuery(t *testing.T) {
+	t.Parallel()
+	server := &recordedServer{
+		responses: [][]any{
+			{map[any]any{"id": "a", "label": "unregistered_city", "name": []any{"Aston"}, "createdAt": []any{int64(5)}}},
+			{},
+		},
+	}
+	db := driver.NewScriptDriverForTest(
+		driver.Config{Timestamps: driver.Timestamps{CreatedAt: "createdAt"}},
+		server.submit,
+	)
+
+	subgraph, err := driver.Subgraph(db, []any{"a"}, 2, "road")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := server.requests[0].resolvedScript()
+	if !regexp.MustCompile(`\.dedup\(\)\.where\(P\.without\(.*\)\)\.aggregate\("subgraph_seen"\)\)\.times`).MatchString(script) {
+		t.Errorf("Expected the repeat to skip vertices already seen, got %s", script)
+	}
+	want := map[string]any{"name": "Aston", gsmtypes.CreatedAt: int64(5)}
+	if len(subgraph.Vertices) != 1 || !reflect.DeepEqual(subgraph.Vertices[0].Properties, want) {
+		t.Errorf("Expected properties %v, got %+v", want, subgraph.Vertices)
+	}
+}
+
+func TestSubgraph(t *testing.T) {
+	db, err := driver.Open(
+		DbURL, driver.Config{
+			Dialect: dbDialect,
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanDB)
+	cities := seedCities(t, db)
+
+	subgraph, err := driver.Subgraph(db, []any{cities["a"].ID}, 1, "road")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a plus its direct neighbours b and c; island is not connected
+	if len(subgraph.Vertices) != 3 {
+		t.Fatalf("Expected 3 vertices, got %d", len(subgraph.Vertices))
+	}
+	// a->b, a->c and the b->c edge between the collected vertices
+	if len(subgraph.Edges) != 3 {
+		t.Fatalf("Expected 3 edges, got %d", len(subgraph.Edges))
+	}
+	for _, vertex := range subgraph.Vertices {
+		if vertex.Label != "test_city" || vertex.Properties["name"] == nil {
+			t.Errorf("Expected city vertex with properties, got %+v", vertex)
+		}
+		if _, ok := vertex.Value.(map[string]any); !ok {
+			t.Errorf("Expected unregistered vertex value to be a map, got %T", vertex.Value)
+		}
+	}
+	for _, edge := range subgraph.Edges {
+		if edge.Label != "road" || edge.Properties["distance"] == nil {
+			t.Errorf("Expected road edge with distance, got %+v", edge)
+		}
+	}
+
+	only, err := driver.Subgraph(db, []any{cities["island"].ID}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(only.Vertices) != 1 || len(only.Edges) != 0 {
+		t.Errorf("Expected only the island vertex, got %+v", only)
+	}
+}
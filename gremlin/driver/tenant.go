@@ -0,0 +1,54 @@
+package driver
+
+import (
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// defaultTenantKey is the partition key property used when
+// Config.TenantKey is empty.
+const defaultTenantKey = "tenant_id"
+
+// tenantKeyFor returns the tenant key configured by config.
+func tenantKeyFor(config Config) string {
+	if config.TenantKey == "" {
+		return defaultTenantKey
+	}
+	return config.TenantKey
+}
+
+// ForTenant returns a view of the driver confined to the partition of
+// tenant: every vertex and edge it writes gets the Config.TenantKey property
+// set to tenant, and every vertex and edge it reads must have it. This
+// applies to all queries run through the view, including Create, Find,
+// Preload, Updates, Delete and traversals built from its G().
+//
+// The partition is enforced with TinkerPop's PartitionStrategy, or on
+// dialects without FeatureStrategies by injecting the equivalent has() and
+// property() steps into each traversal before it is submitted.
+//
+// The view shares the connections of driver, so closing it is a no-op;
+// close driver instead.
+func (driver *GremlinDriver) ForTenant(tenant string) *GremlinDriver {
+	view := driver.view()
+	if driver.dialect.Supports(FeatureStrategies) {
+		view.g = driver.g.WithStrategies(
+			gremlingo.PartitionStrategy(
+				gremlingo.PartitionStrategyConfig{
+					PartitionKey:   driver.tenantKey,
+					WritePartition: tenant,
+					ReadPartitions: gremlingo.NewSimpleSet(tenant),
+				},
+			),
+		)
+		return view
+	}
+	partition := step{operator: "has", args: []any{driver.tenantKey, tenant}}
+	view.injected = view.injected.with(
+		injectedSteps{
+			vertexFilters: []step{partition},
+			edgeFilters:   []step{partition},
+			writes:        []step{{operator: "property", args: []any{driver.tenantKey, tenant}}},
+		},
+	)
+	return view
+}
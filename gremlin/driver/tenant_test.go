@@ -0,0 +1,165 @@
+package driver_test
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+// resolvedScript returns the request script with its bindings substituted,
+// strings quoted.
+func (r recordedRequest) resolvedScript() string {
+	names := make([]string, 0, len(r.bindings))
+	for name := range r.bindings {
+		names = append(names, name)
+	}
+	// p10 must be replaced before p1.
+	slices.SortFunc(names, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	script := r.script
+	for _, name := range names {
+		value := fmt.Sprintf("%v", r.bindings[name])
+		if _, ok := r.bindings[name].(string); ok {
+			value = fmt.Sprintf("%q", value)
+		}
+		script = strings.ReplaceAll(script, name, value)
+	}
+	return script
+}
+
+func TestForTenant(t *testing.T) {
+	t.Parallel()
+	const (
+		inPartition  = `.has("tenant_id", "acme")`
+		setPartition = `.property("tenant_id", "acme")`
+	)
+	tests := []struct {
+		name string
+		run  func(db *driver.GremlinDriver) error
+		want []string
+	}{
+		{
+			name: "Find",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testTopic](db).Where("title", comparator.EQ, "graphs").Find()
+				return err
+			},
+			want: []string{`g.V()` + inPartition + `.hasLabel("test_topic")`},
+		},
+		{
+			name: "ID",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testTopic](db).ID("1")
+				return err
+			},
+			want: []string{`g.V("1")` + inPartition},
+		},
+		{
+			name: "Create",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Create(db, &testTopic{Title: "graphs"})
+			},
+			want: []string{`.addV("test_topic")` + setPartition},
+		},
+		{
+			name: "Preload",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testPerson](db).Preload("Topics").Find()
+				return err
+			},
+			want: []string{`__.outE("subscribed")` + inPartition + `.inV()` + inPartition},
+		},
+		{
+			name: "Updates",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testTopic](db).
+					Where("title", comparator.EQ, "graphs").
+					Updates(map[string]any{"title": "trees"})
+			},
+			want: []string{`g.V()` + inPartition},
+		},
+		{
+			name: "Delete",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testTopic](db).Where("title", comparator.EQ, "graphs").Delete()
+			},
+			want: []string{`g.V()` + inPartition, `.drop()`},
+		},
+		{
+			name: "AddEdge",
+			run: func(db *driver.GremlinDriver) error {
+				return db.Iterate(db.G().V("1").AddE("subscribed").To(gremlingo.T__.V("2")))
+			},
+			want: []string{
+				`g.V("1")` + inPartition,
+				`.addE("subscribed").to(__.V("2")` + inPartition + `)` + setPartition,
+			},
+		},
+		{
+			name: "ReadEdges",
+			run: func(db *driver.GremlinDriver) error {
+				_, err := db.ToList(db.G().E().OutV().ElementMap())
+				return err
+			},
+			want: []string{`g.E()` + inPartition + `.outV()` + inPartition + `.elementMap()`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{map[any]any{"id": "1"}}}}
+				db := driver.NewScriptDriverForTest(
+					driver.Config{Dialect: driver.CosmosDBDialect{}},
+					server.submit,
+				)
+				if err := tt.run(db.ForTenant("acme")); err != nil {
+					t.Fatal(err)
+				}
+				script := server.requests[0].resolvedScript()
+				for _, want := range tt.want {
+					if !strings.Contains(script, want) {
+						t.Errorf("Expected script to contain %s, got %s", want, script)
+					}
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"PartitionStrategy", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{TenantKey: "org"}, server.submit)
+			if _, err := driver.Model[testTopic](db.ForTenant("acme")).Find(); err != nil {
+				t.Fatal(err)
+			}
+			script := server.requests[0].resolvedScript()
+			want := `g.withStrategies(new PartitionStrategy(includeMetaProperties: false, ` +
+				`partitionKey: "org", readPartitions: [acme], writePartition: "acme")).V()`
+			if !strings.HasPrefix(script, want) {
+				t.Errorf("Expected script to start with %s, got %s", want, script)
+			}
+		},
+	)
+
+	t.Run(
+		"LeavesDriverUnpartitioned", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}, {}}}
+			db := driver.NewScriptDriverForTest(driver.Config{Dialect: driver.CosmosDBDialect{}}, server.submit)
+			_ = db.ForTenant("acme")
+			if _, err := driver.Model[testTopic](db).Find(); err != nil {
+				t.Fatal(err)
+			}
+			if slices.Contains(server.requests[0].bindingValues(), any("acme")) {
+				t.Errorf("Expected no tenant filter, got %s", server.requests[0].resolvedScript())
+			}
+		},
+	)
+}
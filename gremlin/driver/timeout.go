@@ -0,0 +1,83 @@
+package driver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// Timeout bounds the query on the server, through the request's
+// evaluationTimeout, and on the client, which stops waiting for the response
+// after d. Either way the query fails with ErrTimeout. It overrides
+// Config.QueryTimeout.
+func (q *Query[T]) Timeout(d time.Duration) *Query[T] {
+	q.writeDebugString(fmt.Sprintf(".Timeout(%s)", d))
+	if d <= 0 {
+		q.err = fmt.Errorf("Timeout: duration must be positive, got %s", d)
+		return q
+	}
+	view := q.db.view()
+	view.queryTimeout = d
+	q.db = view
+	return q
+}
+
+// applyTimeout returns the timeout traversal runs under, the driver's
+// queryTimeout, and traversal with that timeout set as its
+// evaluationTimeout. The timeout is set on a copy, leaving traversal
+// untouched for callers that reuse it.
+func (driver *GremlinDriver) applyTimeout(
+	traversal *gremlingo.GraphTraversal,
+) (*gremlingo.GraphTraversal, time.Duration) {
+	if driver.queryTimeout <= 0 {
+		return traversal, 0
+	}
+	timed := traversal.Clone()
+	_ = timed.Bytecode.AddSource(
+		"withStrategies",
+		gremlingo.OptionsStrategy(map[string]any{evaluationTimeoutArg: driver.queryTimeout.Milliseconds()}),
+	)
+	return timed, driver.queryTimeout
+}
+
+// withDeadline runs fn, giving up with ErrTimeout after timeout. fn keeps
+// running in the background when it is abandoned. A zero timeout waits for
+// fn to return.
+func withDeadline[R any](timeout time.Duration, fn func() (R, error)) (R, error) {
+	if timeout <= 0 {
+		return fn()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	result, err := runWithContext(ctx, fn)
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = &GremlinError{
+			Kind: ErrTimeout,
+			Err:  fmt.Errorf("no response within %s: %w", timeout, context.DeadlineExceeded),
+		}
+	}
+	return result, err
+}
+
+// runWithContext runs fn, giving up when ctx is done.
+func runWithContext[R any](ctx context.Context, fn func() (R, error)) (R, error) {
+	type outcome struct {
+		result R
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := fn()
+		done <- outcome{result, err}
+	}()
+	select {
+	case o := <-done:
+		return o.result, o.err
+	case <-ctx.Done():
+		var zero R
+		return zero, classifyError(ctx.Err())
+	}
+}
@@ -0,0 +1,119 @@
+package driver_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+func TestQueryTimeout(t *testing.T) {
+	t.Parallel()
+	topic := map[any]any{"id": "1", "label": "test_topic", "title": "graphs"}
+
+	t.Run(
+		"SetsEvaluationTimeout", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{topic}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Timeout(250 * time.Millisecond).Find(); err != nil {
+				t.Fatal(err)
+			}
+			request := server.requests[0]
+			if got := request.requestArgs["evaluationTimeout"]; got != int64(250) {
+				t.Errorf("Expected evaluationTimeout 250, got %v", got)
+			}
+			if strings.Contains(request.script, ".with(") {
+				t.Errorf("Expected evaluationTimeout to be sent as a request argument, got %s", request.script)
+			}
+		},
+	)
+
+	t.Run(
+		"ConfigDefaultAndOverride", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{topic}, {topic}, {"1"}}}
+			db := driver.NewScriptDriverForTest(driver.Config{QueryTimeout: 2 * time.Second}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Find(); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := driver.Model[testTopic](db).Timeout(100 * time.Millisecond).ID("1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := driver.Create(db, &testTopic{Title: "graphs"}); err != nil {
+				t.Fatal(err)
+			}
+			want := []any{int64(2000), int64(100), int64(2000)}
+			for i, request := range server.requests {
+				if got := request.requestArgs["evaluationTimeout"]; got != want[i] {
+					t.Errorf("Request %d: expected evaluationTimeout %v, got %v", i, want[i], got)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"LeavesTraversalUntouched", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{topic}, {topic}}}
+			timed := driver.NewScriptDriverForTest(driver.Config{QueryTimeout: 2 * time.Second}, server.submit)
+			untimed := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			traversal := timed.G().V()
+			if _, err := timed.ToList(traversal); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := untimed.ToList(traversal); err != nil {
+				t.Fatal(err)
+			}
+			if got := server.requests[0].requestArgs["evaluationTimeout"]; got != int64(2000) {
+				t.Errorf("Expected evaluationTimeout 2000, got %v", got)
+			}
+			if got, ok := server.requests[1].requestArgs["evaluationTimeout"]; ok {
+				t.Errorf("Expected the reused traversal to carry no evaluationTimeout, got %v", got)
+			}
+		},
+	)
+
+	t.Run(
+		"ClientDeadline", func(t *testing.T) {
+			t.Parallel()
+			release := make(chan struct{})
+			t.Cleanup(func() { close(release) })
+			hanging := func(string, map[string]any, map[string]any) ([]*gremlingo.Result, error) {
+				<-release
+				return nil, nil
+			}
+			db := driver.NewScriptDriverForTest(driver.Config{}, hanging)
+
+			start := time.Now()
+			_, err := driver.Model[testTopic](db).Timeout(20 * time.Millisecond).Find()
+			if !errors.Is(err, driver.ErrTimeout) {
+				t.Errorf("Expected ErrTimeout, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected the query to give up after its timeout, took %s", elapsed)
+			}
+		},
+	)
+
+	t.Run(
+		"RejectsNonPositive", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			if _, err := driver.Model[testTopic](db).Timeout(0).Find(); err == nil {
+				t.Error("Expected an error for a zero timeout")
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+}
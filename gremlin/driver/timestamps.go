@@ -0,0 +1,204 @@
+package driver
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+// TimestampFormat selects how created and last modified timestamps are
+// stored.
+type TimestampFormat int
+
+const (
+	// TimestampTime stores timestamps as dates (default).
+	TimestampTime TimestampFormat = iota
+	// TimestampEpochMillis stores timestamps as milliseconds since the Unix
+	// epoch.
+	TimestampEpochMillis
+	// TimestampRFC3339 stores timestamps as RFC 3339 strings with
+	// nanoseconds.
+	TimestampRFC3339
+)
+
+// Timestamps configures the created and last modified timestamps the
+// driver maintains on vertices.
+type Timestamps struct {
+	// CreatedAt and LastModified are the property names (default
+	// "created_at" and "last_modified"). The gsmtypes.Vertex and
+	// gsmtypes.Edge fields are loaded from them whatever they are called.
+	CreatedAt    string
+	LastModified string
+	// Format is the stored representation (default TimestampTime).
+	Format TimestampFormat
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+var noTimestampsType = reflect.TypeFor[gsmtypes.NoTimestampsType]()
+
+// typeDisablesTimestamps reports whether rt is a struct implementing
+// gsmtypes.NoTimestampsType, typically by embedding gsmtypes.NoTimestamps.
+func typeDisablesTimestamps(rt reflect.Type) bool {
+	return rt.Kind() == reflect.Struct && reflect.PointerTo(rt).Implements(noTimestampsType)
+}
+
+// timestamps is the resolved timestamp configuration of a driver.
+type timestamps struct {
+	clock        func() time.Time
+	createdAt    string
+	lastModified string
+	format       TimestampFormat
+}
+
+// timestampsFor returns the timestamp configuration of config with the
+// defaults filled in.
+func timestampsFor(config Config) timestamps {
+	t := timestamps{
+		clock:        config.Clock,
+		createdAt:    config.Timestamps.CreatedAt,
+		lastModified: config.Timestamps.LastModified,
+		format:       config.Timestamps.Format,
+	}
+	if t.clock == nil {
+		t.clock = time.Now
+	}
+	if t.createdAt == "" {
+		t.createdAt = gsmtypes.CreatedAt
+	}
+	if t.lastModified == "" {
+		t.lastModified = gsmtypes.LastModified
+	}
+	return t
+}
+
+// Timestamp returns the current time of Config.Clock in the configured
+// TimestampFormat, for setting the created and last modified timestamps of
+// elements written with G(), such as edges:
+//
+//	now := db.Timestamp()
+//	err := db.Iterate(db.G().V(from).AddE("follows").To(gremlingo.T__.V(to)).
+//		Property("created_at", now).
+//		Property("last_modified", now))
+func (driver *GremlinDriver) Timestamp() any {
+	return driver.timestamps.value(driver.timestamps.now())
+}
+
+// Now returns the current time of Config.Clock in UTC.
+func (driver *GremlinDriver) Now() time.Time {
+	return driver.timestamps.now()
+}
+
+// now returns the current time of the configured clock in UTC.
+func (t timestamps) now() time.Time {
+	return t.clock().UTC()
+}
+
+// value returns tm in the configured storage format.
+func (t timestamps) value(tm time.Time) any {
+	switch t.format {
+	case TimestampEpochMillis:
+		return tm.UnixMilli()
+	case TimestampRFC3339:
+		return tm.Format(time.RFC3339Nano)
+	}
+	return tm
+}
+
+// encode moves the created_at and last_modified entries that structToMap
+// read from gsmtypes.Vertex to the configured property names and format.
+// They are dropped for models that disable timestamps. A deleted_at entry
+// of gsmtypes.SoftDelete keeps its name but is stored in the same format.
+func (t timestamps) encode(properties map[string]any, enabled bool) {
+	if tm, isTime := properties[gsmtypes.DeletedAt].(time.Time); isTime {
+		properties[gsmtypes.DeletedAt] = t.value(tm)
+	}
+	for canonical, name := range map[string]string{
+		gsmtypes.CreatedAt:    t.createdAt,
+		gsmtypes.LastModified: t.lastModified,
+	} {
+		value, ok := properties[canonical]
+		if !ok {
+			continue
+		}
+		delete(properties, canonical)
+		if tm, isTime := value.(time.Time); enabled && isTime {
+			properties[name] = t.value(tm)
+		}
+	}
+}
+
+// renamed reports whether the timestamp property names differ from those of
+// the gsmtypes.Vertex tags.
+func (t timestamps) renamed() bool {
+	return t.createdAt != gsmtypes.CreatedAt || t.lastModified != gsmtypes.LastModified
+}
+
+// canonical returns value with the configured timestamp property names in
+// every nested value map renamed to those of the gsmtypes.Vertex tags, so
+// query results unload into its fields. value is not modified.
+func (t timestamps) canonical(value any) any {
+	if !t.renamed() {
+		return value
+	}
+	switch typed := value.(type) {
+	case map[any]any:
+		renamed := make(map[any]any, len(typed))
+		for key, item := range typed {
+			switch key {
+			case t.createdAt:
+				key = gsmtypes.CreatedAt
+			case t.lastModified:
+				key = gsmtypes.LastModified
+			}
+			renamed[key] = t.canonical(item)
+		}
+		return renamed
+	case []any:
+		renamed := make([]any, len(typed))
+		for i, item := range typed {
+			renamed[i] = t.canonical(item)
+		}
+		return renamed
+	}
+	return value
+}
+
+// selectFields returns the valueMap arguments fields with the gsmtypes.Vertex
+// timestamp names replaced by the configured ones.
+func (t timestamps) selectFields(fields []any) []any {
+	if fields == nil || !t.renamed() {
+		return fields
+	}
+	selected := make([]any, len(fields))
+	for i, field := range fields {
+		switch field {
+		case gsmtypes.CreatedAt:
+			field = t.createdAt
+		case gsmtypes.LastModified:
+			field = t.lastModified
+		}
+		selected[i] = field
+	}
+	return selected
+}
+
+// parseTimestamp converts a timestamp stored in any TimestampFormat back to
+// a time.Time.
+func parseTimestamp(value any) (time.Time, bool) {
+	switch typed := value.(type) {
+	case time.Time:
+		return typed, true
+	case int64:
+		return time.UnixMilli(typed).UTC(), true
+	case int32:
+		return time.UnixMilli(int64(typed)).UTC(), true
+	case int:
+		return time.UnixMilli(int64(typed)).UTC(), true
+	case string:
+		tm, err := time.Parse(time.RFC3339Nano, typed)
+		return tm, err == nil
+	}
+	return time.Time{}, false
+}
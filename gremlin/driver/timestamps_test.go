@@ -0,0 +1,200 @@
+package driver_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testUntimedTopic struct {
+	gsmtypes.Vertex
+	gsmtypes.NoTimestamps
+	Title string `json:"title" gremlin:"title"`
+}
+
+var testClockTime = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+func testClock() time.Time { return testClockTime.In(time.FixedZone("CEST", 2*60*60)) }
+
+func TestTimestamps(t *testing.T) {
+	t.Parallel()
+	renamed := driver.Timestamps{CreatedAt: "createdAt", LastModified: "updatedAt"}
+	tests := []struct {
+		name       string
+		timestamps driver.Timestamps
+		run        func(db *driver.GremlinDriver) error
+		want       string
+		notWant    []string
+	}{
+		{
+			name: "CreateTime",
+			run:  func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+			want: `.property(single, "created_at", 2024-05-01 12:30:00 +0000 UTC)`,
+		},
+		{
+			name:       "CreateEpochMillis",
+			timestamps: driver.Timestamps{Format: driver.TimestampEpochMillis},
+			run:        func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+			want:       `.property(single, "last_modified", 1714566600000)`,
+		},
+		{
+			name:       "CreateRFC3339",
+			timestamps: driver.Timestamps{Format: driver.TimestampRFC3339},
+			run:        func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+			want:       `.property(single, "created_at", "2024-05-01T12:30:00Z")`,
+		},
+		{
+			name:       "CreateRenamed",
+			timestamps: renamed,
+			run:        func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+			want:       `.property(single, "createdAt", 2024-05-01 12:30:00 +0000 UTC)`,
+			notWant:    []string{`"created_at"`, `"last_modified"`},
+		},
+		{
+			name:       "Updates",
+			timestamps: renamed,
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testTopic](db).
+					Where("title", comparator.EQ, "graphs").
+					Updates(map[string]any{"title": "trees"})
+			},
+			want:    `.property(single, "updatedAt", 2024-05-01 12:30:00 +0000 UTC)`,
+			notWant: []string{`"last_modified"`},
+		},
+		{
+			name:       "SoftDeleteEpochMillis",
+			timestamps: driver.Timestamps{Format: driver.TimestampEpochMillis},
+			run:        func(db *driver.GremlinDriver) error { return archivedPosts(db).Delete() },
+			want:       `.property(single, "deleted_at", 1714566600000)`,
+		},
+		{
+			name:       "SaveDeletedRFC3339",
+			timestamps: driver.Timestamps{Format: driver.TimestampRFC3339},
+			run: func(db *driver.GremlinDriver) error {
+				post := testArchivedPost{Title: "graphs"}
+				post.DeletedAt = testClockTime
+				return driver.Create(db, &post)
+			},
+			want: `.property(single, "deleted_at", "2024-05-01T12:30:00Z")`,
+		},
+		{
+			name:    "CreateDisabled",
+			run:     func(db *driver.GremlinDriver) error { return driver.Create(db, &testUntimedTopic{Title: "graphs"}) },
+			want:    `.addV("test_untimed_topic").property(single, "title", "graphs")`,
+			notWant: []string{`"created_at"`, `"last_modified"`},
+		},
+		{
+			name: "UpdatesDisabled",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testUntimedTopic](db).
+					Where("title", comparator.EQ, "graphs").
+					Updates(map[string]any{"title": "trees"})
+			},
+			want:    `.property(single, "title", "trees")`,
+			notWant: []string{`"last_modified"`},
+		},
+		{
+			name:       "FindSelectsRenamed",
+			timestamps: renamed,
+			run: func(db *driver.GremlinDriver) error {
+				_, err := driver.Model[testPerson](db).Preload("Topics").Find()
+				return err
+			},
+			want:    `__.out("subscribed").hasLabel("test_topic").valueMap(true, "id", "updatedAt", "createdAt", "title")`,
+			notWant: []string{`"created_at"`, `"last_modified"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{map[any]any{"id": "1"}}}}
+				db := driver.NewScriptDriverForTest(
+					driver.Config{Clock: testClock, Timestamps: tt.timestamps},
+					server.submit,
+				)
+				if err := tt.run(db); err != nil {
+					t.Fatal(err)
+				}
+				script := server.requests[0].resolvedScript()
+				if !strings.Contains(script, tt.want) {
+					t.Errorf("Expected script to contain %s, got %s", tt.want, script)
+				}
+				for _, notWant := range tt.notWant {
+					if strings.Contains(script, notWant) {
+						t.Errorf("Expected script not to contain %s, got %s", notWant, script)
+					}
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"CreateSetsFields", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{"1"}}}
+			db := driver.NewScriptDriverForTest(driver.Config{Clock: testClock}, server.submit)
+			topic := testTopic{Title: "graphs"}
+			if err := driver.Create(db, &topic); err != nil {
+				t.Fatal(err)
+			}
+			if topic.CreatedAt != testClockTime || topic.LastModified != testClockTime {
+				t.Errorf("Expected timestamps %v in UTC, got %v and %v", testClockTime, topic.CreatedAt, topic.LastModified)
+			}
+		},
+	)
+
+	t.Run(
+		"LoadsAnyFormat", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{
+				responses: [][]any{
+					{
+						map[any]any{
+							"id":        "1",
+							"name":      "Ann",
+							"createdAt": testClockTime.UnixMilli(),
+							"updatedAt": testClockTime.Format(time.RFC3339Nano),
+							"Topics": []any{
+								map[any]any{"id": "2", "title": "graphs", "createdAt": testClockTime},
+							},
+						},
+					},
+				},
+			}
+			db := driver.NewScriptDriverForTest(driver.Config{Timestamps: renamed}, server.submit)
+			people, err := driver.Model[testPerson](db).Preload("Topics").Find()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(people) != 1 || len(people[0].Topics) != 1 {
+				t.Fatalf("Expected a person with a topic, got %+v", people)
+			}
+			loaded := []time.Time{people[0].CreatedAt, people[0].LastModified, people[0].Topics[0].CreatedAt}
+			if slices.ContainsFunc(loaded, func(tm time.Time) bool { return !tm.Equal(testClockTime) }) {
+				t.Errorf("Expected every timestamp to be %v, got %v", testClockTime, loaded)
+			}
+		},
+	)
+
+	t.Run(
+		"Timestamp", func(t *testing.T) {
+			t.Parallel()
+			db := driver.NewScriptDriverForTest(
+				driver.Config{Clock: testClock, Timestamps: driver.Timestamps{Format: driver.TimestampEpochMillis}},
+				(&recordedServer{}).submit,
+			)
+			if got := db.Timestamp(); got != testClockTime.UnixMilli() {
+				t.Errorf("Expected %d, got %v", testClockTime.UnixMilli(), got)
+			}
+			if got := db.Now(); !got.Equal(testClockTime) || got.Location() != time.UTC {
+				t.Errorf("Expected %v in UTC, got %v", testClockTime, got)
+			}
+		},
+	)
+}
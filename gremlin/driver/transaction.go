@@ -33,7 +33,20 @@ var (
 // TinkerGraph does not support transactions; Gremlin Server backed by a
 // transaction-capable graph (e.g. JanusGraph, Neptune, TinkerTransactionGraph)
 // is required.
+//
+// When Config.Retry is set, a transaction that fails with a retryable error
+// is rolled back and fn is run again in a new transaction, so fn must not
+// have side effects outside the transaction.
 func (driver *GremlinDriver) Transaction(fn func(tx *GremlinDriver) error) error {
+	return driver.retry(
+		"transaction", func() error {
+			return driver.runTransaction(fn)
+		},
+	)
+}
+
+// runTransaction is a single attempt of Transaction.
+func (driver *GremlinDriver) runTransaction(fn func(tx *GremlinDriver) error) error {
 	tx, err := driver.Begin()
 	if err != nil {
 		return err
@@ -63,18 +76,34 @@ func (driver *GremlinDriver) Begin() (*GremlinDriver, error) {
 	if driver.tx != nil {
 		return nil, ErrNestedTransaction
 	}
+	if !driver.dialect.Supports(FeatureTransactions) {
+		return nil, fmt.Errorf("%w by %s", ErrTransactionUnsupported, driver.dialect.Name())
+	}
+	if driver.scripts != nil {
+		return nil, fmt.Errorf("%w in script mode", ErrTransactionUnsupported)
+	}
 	tx := driver.g.Tx()
 	gtx, err := tx.Begin()
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		return nil, fmt.Errorf("failed to begin transaction: %w", classifyError(err))
 	}
 	return &GremlinDriver{
-		remoteConn:  driver.remoteConn,
-		g:           gtx,
-		logger:      driver.logger,
-		dbDriver:    driver.dbDriver,
-		idGenerator: driver.idGenerator,
-		tx:          tx,
+		remoteConn:   driver.remoteConn,
+		connection:   driver.connection,
+		g:            gtx,
+		logger:       driver.logger,
+		dialect:      driver.dialect,
+		idGenerator:  driver.idGenerator,
+		queryTimeout: driver.queryTimeout,
+		guardrails:   driver.guardrails,
+		validator:    driver.validator,
+		tx:           tx,
+		tenantKey:    driver.tenantKey,
+		injected:     driver.injected,
+		readOnly:     driver.readOnly,
+		timestamps:   driver.timestamps,
+		auditSink:    driver.auditSink,
+		ctx:          driver.ctx,
 	}, nil
 }
 
@@ -84,7 +113,7 @@ func (driver *GremlinDriver) Commit() error {
 		return ErrNotInTransaction
 	}
 	if err := driver.tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("failed to commit transaction: %w", classifyError(err))
 	}
 	return nil
 }
@@ -95,7 +124,7 @@ func (driver *GremlinDriver) Rollback() error {
 		return ErrNotInTransaction
 	}
 	if err := driver.tx.Rollback(); err != nil {
-		return fmt.Errorf("failed to rollback transaction: %w", err)
+		return fmt.Errorf("failed to rollback transaction: %w", classifyError(err))
 	}
 	return nil
 }
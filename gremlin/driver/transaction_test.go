@@ -13,7 +13,7 @@ func openTestDB(t *testing.T) *driver.GremlinDriver {
 	t.Helper()
 	db, err := driver.Open(
 		DbURL, driver.Config{
-			Driver: dbDriver,
+			Dialect: dbDialect,
 		},
 	)
 	if err != nil {
@@ -0,0 +1,201 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// ErrUniqueViolation is matched by UniqueViolationError.
+var ErrUniqueViolation = errors.New("unique constraint violation")
+
+// UniqueViolationError is returned by writes that would give a second vertex
+// the values of a unique constraint declared with the unique tag option.
+type UniqueViolationError struct {
+	// Label is the label the constraint applies to.
+	Label string
+	// Fields are the properties of the violated constraint.
+	Fields []string
+	// VertexID is the id of the vertex already holding the values.
+	VertexID any
+}
+
+func (e *UniqueViolationError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s %s is already used by vertex %v",
+		ErrUniqueViolation, e.Label, strings.Join(e.Fields, ", "), e.VertexID,
+	)
+}
+
+func (e *UniqueViolationError) Unwrap() error { return ErrUniqueViolation }
+
+// Keys of the map a guarded write returns instead of its own result when it
+// finds a conflicting vertex.
+const (
+	uniqueConflictKey = "gsm_unique_conflict"
+	uniqueVertexKey   = "gsm_unique_vertex"
+	uniqueLabelKey    = "gsm_unique_label"
+)
+
+// uniqueGroupsWithValues returns the groups whose every property is set in
+// properties. Unset values, such as nil pointers and omitempty zero values,
+// are not checked.
+func uniqueGroupsWithValues(groups []uniqueGroup, properties map[string]any) []uniqueGroup {
+	var set []uniqueGroup
+	for _, group := range groups {
+		complete := true
+		for _, field := range group.fields {
+			if _, ok := properties[field]; !ok {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			set = append(set, group)
+		}
+	}
+	return set
+}
+
+// uniquePredicate matches a property holding value, or any element of value
+// for slices.
+func uniquePredicate(value any) any {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return value
+	}
+	values := make([]any, rv.Len())
+	for i := range rv.Len() {
+		values[i] = rv.Index(i).Interface()
+	}
+	return gremlingo.P.Within(values)
+}
+
+// uniqueFilter matches vertices holding group's values in properties.
+func uniqueFilter(group uniqueGroup, properties map[string]any) *gremlingo.GraphTraversal {
+	filter := anonymousTraversal.Identity()
+	for _, field := range group.fields {
+		filter = filter.Has(field, uniquePredicate(properties[field]))
+	}
+	return filter
+}
+
+// projectUniqueConflict turns the conflicting vertex reached by conflict
+// into the map uniqueViolation reads; name is a traversal producing the
+// violated group's name.
+func projectUniqueConflict(conflict *gremlingo.GraphTraversal, name *gremlingo.GraphTraversal) *gremlingo.GraphTraversal {
+	return conflict.Project(uniqueVertexKey, uniqueLabelKey, uniqueConflictKey).
+		By(anonymousTraversal.Id()).
+		By(anonymousTraversal.Label()).
+		By(name)
+}
+
+// uniqueGuard wraps write, an anonymous traversal writing a vertex labelled
+// label with properties, in a fold/coalesce guard: write only runs when no
+// vertex other than self holds the values of one of groups. Otherwise the
+// traversal returns the conflict for uniqueViolation. self is nil for
+// creates.
+func uniqueGuard(
+	db *GremlinDriver,
+	label string,
+	groups []uniqueGroup,
+	properties map[string]any,
+	self any,
+	write *gremlingo.GraphTraversal,
+) *gremlingo.GraphTraversal {
+	filters := make([]any, len(groups))
+	matchers := make([]any, len(groups))
+	for i, group := range groups {
+		filters[i] = uniqueFilter(group, properties)
+		matchers[i] = uniqueFilter(group, properties).Constant(group.name)
+	}
+	candidates := db.g.V().HasLabel(label).Or(filters...)
+	if self != nil {
+		candidates = candidates.Not(anonymousTraversal.HasId(self))
+	}
+	return candidates.Limit(1).Fold().Coalesce(
+		projectUniqueConflict(anonymousTraversal.Unfold(), anonymousTraversal.Coalesce(matchers...)),
+		write,
+	)
+}
+
+// uniqueUpdateConflicts returns one coalesce branch per group touched by
+// properties for an update of the folded vertices matched by a query for
+// labels. A branch finds another vertex with the same label that would share
+// the group's values after the update, taking the values properties does not
+// change from the updated vertex. Groups set entirely by properties also
+// conflict when the update matches more than one vertex.
+func uniqueUpdateConflicts(labels []any, groups []uniqueGroup, properties map[string]any) []any {
+	var conflicts []any
+	for _, group := range groups {
+		touched, complete := false, true
+		for _, field := range group.fields {
+			if _, ok := properties[field]; ok {
+				touched = true
+			} else {
+				complete = false
+			}
+		}
+		if !touched {
+			continue
+		}
+		conflict := anonymousTraversal.Unfold().As("v").V()
+		if len(labels) > 0 {
+			conflict = conflict.HasLabel(labels...)
+		}
+		conflict = conflict.Where(gremlingo.P.Neq("v")).Where(gremlingo.P.Eq("v")).By(gremlingo.T.Label)
+		for _, field := range group.fields {
+			if value, ok := properties[field]; ok {
+				conflict = conflict.Has(field, uniquePredicate(value))
+			} else {
+				conflict = conflict.Where(gremlingo.P.Eq("v")).By(field)
+			}
+		}
+		conflicts = append(
+			conflicts,
+			projectUniqueConflict(conflict.Limit(1), anonymousTraversal.Constant(group.name)),
+		)
+		if complete {
+			conflicts = append(
+				conflicts,
+				projectUniqueConflict(
+					anonymousTraversal.Where(anonymousTraversal.Count(Scope.Local).Is(gremlingo.P.Gt(1))).
+						Unfold().
+						Limit(1),
+					anonymousTraversal.Constant(group.name),
+				),
+			)
+		}
+	}
+	return conflicts
+}
+
+// uniqueViolation returns the UniqueViolationError reported by the result of
+// a guarded write, or nil when the write went ahead.
+func uniqueViolation(groups []uniqueGroup, result *gremlingo.Result) error {
+	if result == nil {
+		return nil
+	}
+	conflict, ok := result.GetInterface().(map[any]any)
+	if !ok {
+		return nil
+	}
+	name, ok := conflict[uniqueConflictKey].(string)
+	if !ok {
+		return nil
+	}
+	violation := &UniqueViolationError{
+		Label:    fmt.Sprint(conflict[uniqueLabelKey]),
+		Fields:   []string{name},
+		VertexID: conflict[uniqueVertexKey],
+	}
+	for _, group := range groups {
+		if group.name == name {
+			violation.Fields = group.fields
+		}
+	}
+	return violation
+}
@@ -0,0 +1,138 @@
+package driver_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testUniqueUser struct {
+	gsmtypes.Vertex
+	Email string `json:"email" gremlin:"email,unique"`
+	First string `json:"first" gremlin:"first,unique=full_name"`
+	Last  string `json:"last"  gremlin:"last,unique=full_name"`
+	Bio   string `json:"bio"   gremlin:"bio,omitempty"`
+}
+
+func uniqueConflict(id any, group string) map[any]any {
+	return map[any]any{
+		"gsm_unique_vertex":   id,
+		"gsm_unique_label":    "test_unique_user",
+		"gsm_unique_conflict": group,
+	}
+}
+
+func TestUniqueConstraints(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"CreateGuardsWrite", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{"new-id"}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			user := testUniqueUser{Email: "ann@example.com", First: "Ann", Last: "Lee"}
+			if err := driver.Create(db, &user); err != nil {
+				t.Fatal(err)
+			}
+			if user.ID != "new-id" {
+				t.Errorf("Expected id new-id, got %v", user.ID)
+			}
+			script := server.requests[0].script
+			for _, want := range []string{".or(", ".limit(", ".fold().coalesce(", "__.addV("} {
+				if !strings.Contains(script, want) {
+					t.Errorf("Expected script to contain %s, got %s", want, script)
+				}
+			}
+		},
+	)
+
+	t.Run(
+		"CreateReportsConflict", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{uniqueConflict("existing", "email")}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			user := testUniqueUser{Email: "ann@example.com", First: "Ann", Last: "Lee"}
+			err := driver.Create(db, &user)
+			var violation *driver.UniqueViolationError
+			if !errors.Is(err, driver.ErrUniqueViolation) || !errors.As(err, &violation) {
+				t.Fatalf("Expected a unique violation, got %v", err)
+			}
+			if violation.VertexID != "existing" || !slices.Equal(violation.Fields, []string{"email"}) {
+				t.Errorf("Unexpected violation %+v", violation)
+			}
+			if user.ID != nil {
+				t.Errorf("Expected no id to be set, got %v", user.ID)
+			}
+		},
+	)
+
+	t.Run(
+		"CompositeGroup", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{uniqueConflict("existing", "full_name")}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			err := driver.Save(db, &testUniqueUser{
+				Vertex: gsmtypes.Vertex{ID: "self"},
+				Email:  "ann@example.com",
+				First:  "Ann",
+				Last:   "Lee",
+			})
+			var violation *driver.UniqueViolationError
+			if !errors.As(err, &violation) {
+				t.Fatalf("Expected a unique violation, got %v", err)
+			}
+			if !slices.Equal(violation.Fields, []string{"first", "last"}) ||
+				violation.Label != "test_unique_user" {
+				t.Errorf("Unexpected violation %+v", violation)
+			}
+			if !strings.Contains(server.requests[0].script, ".not(__.hasId(") {
+				t.Errorf("Expected the saved vertex to be excluded, got %s", server.requests[0].script)
+			}
+		},
+	)
+
+	t.Run(
+		"UpdatesChecksTouchedGroups", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{uniqueConflict("other", "full_name")}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			err := driver.Model[testUniqueUser](db).
+				Where("email", comparator.EQ, "ann@example.com").
+				Updates(map[string]any{"first": "Anne"})
+			if !errors.Is(err, driver.ErrUniqueViolation) {
+				t.Fatalf("Expected a unique violation, got %v", err)
+			}
+			script := server.requests[0].script
+			if !strings.Contains(script, ".fold().coalesce(") || !strings.Contains(script, ".by(label)") {
+				t.Errorf("Expected a guarded update, got %s", script)
+			}
+		},
+	)
+
+	t.Run(
+		"NoGuardWithoutUniqueValues", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			err := driver.Model[testUniqueUser](db).
+				Where("email", comparator.EQ, "ann@example.com").
+				Updates(map[string]any{"bio": "hello"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Contains(server.requests[0].script, "coalesce") {
+				t.Errorf("Expected an unguarded update, got %s", server.requests[0].script)
+			}
+		},
+	)
+}
@@ -179,7 +179,19 @@ func setFieldFromValue(field reflect.Value, value any) {
 	// Assign a Gremlin value into a field, handling slice conversions.
 	gType := reflect.TypeOf(value)
 
+	// Dialects without valueMap() by() support return single-valued
+	// properties as one-element lists.
+	if list, ok := value.([]any); ok && len(list) == 1 && field.Kind() != reflect.Slice {
+		value = list[0]
+		gType = reflect.TypeOf(value)
+	}
+
 	switch {
+	case field.Type() == timeType:
+		// Timestamps may be stored in any TimestampFormat.
+		if tm, ok := parseTimestamp(value); ok {
+			field.Set(reflect.ValueOf(tm))
+		}
 	case gType.ConvertibleTo(field.Type()):
 		field.Set(reflect.ValueOf(value).Convert(field.Type()))
 	case gType.Kind() == reflect.Slice:
@@ -369,24 +381,6 @@ func validateStructPointerWithAnonymousVertex(value any) error {
 	return errors.New("struct must contain anonymous types.Vertex field")
 }
 
-func nextWithDefaultValue[T any](
-	query *gremlingo.GraphTraversal,
-	defaultVal T,
-) (*gremlingo.Result, T, error) {
-	set, err := query.GetResultSet()
-	if err != nil {
-		return nil, defaultVal, err
-	}
-	if set.IsEmpty() {
-		return nil, defaultVal, nil
-	}
-	result, _, err := set.One()
-	if err != nil {
-		return nil, defaultVal, err
-	}
-	return result, defaultVal, nil
-}
-
 func SliceToAnySlice[T any](slice []T) []any {
 	anySlice := make([]any, len(slice))
 	for i, v := range slice {
@@ -0,0 +1,314 @@
+package driver
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validator checks a model before Create, Save and Updates write it.
+// Returning an error aborts the write.
+type Validator interface {
+	Validate(value any) error
+}
+
+// PartialValidator is a Validator that can check a subset of a model's
+// fields. Updates uses it to validate only the updated properties; fields
+// are Go field names, dotted through embedded structs (e.g. "Vertex.ID").
+// Updates are not validated by a Validator that is not a PartialValidator.
+type PartialValidator interface {
+	Validator
+	ValidatePartial(value any, fields ...string) error
+}
+
+// ErrValidation is matched by ValidationErrors.
+var ErrValidation = errors.New("validation failed")
+
+// FieldError is a single failed gsmvalidate rule.
+type FieldError struct {
+	// Field is the Go field name and Property its gremlin property name.
+	Field    string
+	Property string
+	// Rule and Param are the failed rule, e.g. "max" and "150".
+	Rule  string
+	Param string
+	Value any
+}
+
+func (e FieldError) Error() string {
+	switch e.Rule {
+	case "required":
+		return e.Property + " is required"
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", e.Property, e.Param)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", e.Property, e.Param)
+	case "email":
+		return e.Property + " must be a valid email address"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", e.Property, strings.ReplaceAll(e.Param, "|", ", "))
+	}
+	return fmt.Sprintf("%s failed %s", e.Property, e.Rule)
+}
+
+// ValidationErrors collects every rule a model failed.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation, strings.Join(messages, "; "))
+}
+
+func (e ValidationErrors) Unwrap() error { return ErrValidation }
+
+// validationRule is one comma separated entry of a gsmvalidate tag.
+type validationRule struct {
+	name  string
+	param string
+}
+
+// parseValidateTag parses a gsmvalidate tag such as
+// "required,min=0,max=150". Supported rules are omitempty, required, min,
+// max, email and oneof, whose options are separated by |.
+func parseValidateTag(tag string) ([]validationRule, error) {
+	parts := splitTag(tag)
+	rules := make([]validationRule, 0, len(parts))
+	for _, part := range parts {
+		name, param, _ := strings.Cut(part, "=")
+		switch name {
+		case "omitempty", "required", "email":
+			if param != "" {
+				return nil, fmt.Errorf("gsmvalidate rule %s takes no parameter", name)
+			}
+		case "min", "max":
+			if _, err := strconv.ParseFloat(param, 64); err != nil {
+				return nil, fmt.Errorf("gsmvalidate rule %s needs a number, got %q", name, param)
+			}
+		case "oneof":
+			if param == "" {
+				return nil, errors.New("gsmvalidate rule oneof needs options")
+			}
+		default:
+			return nil, fmt.Errorf("unknown gsmvalidate rule %q", name)
+		}
+		rules = append(rules, validationRule{name: name, param: param})
+	}
+	return rules, nil
+}
+
+// TagValidator validates the gsmvalidate tags of gremlin tagged fields. It
+// is the default Validator.
+type TagValidator struct{}
+
+// Validate checks every field of value, a struct or a pointer to one.
+func (TagValidator) Validate(value any) error {
+	return validateTags(value, nil)
+}
+
+// ValidatePartial checks the fields of value named by fields.
+func (TagValidator) ValidatePartial(value any, fields ...string) error {
+	return validateTags(value, fields)
+}
+
+// validateTags checks the fields of value named by fields, or all of them
+// when fields is nil.
+func validateTags(value any, fields []string) error {
+	rv := reflect.ValueOf(value)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	var failed ValidationErrors
+	for _, field := range schemaFor(rv.Type()).mapFields {
+		if field.validationErr != nil {
+			return fmt.Errorf("field %s: %w", field.goName, field.validationErr)
+		}
+		if len(field.validation) == 0 {
+			continue
+		}
+		if fields != nil && !slices.Contains(fields, fieldPath(rv.Type(), field.index)) {
+			continue
+		}
+		if fieldErr, ok := checkRules(field, rv.FieldByIndex(field.index)); !ok {
+			failed = append(failed, fieldErr)
+		}
+	}
+	if len(failed) > 0 {
+		return failed
+	}
+	return nil
+}
+
+// fieldPath returns the dotted Go name of the field at index in rt.
+func fieldPath(rt reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, fieldIndex := range index {
+		field := rt.Field(fieldIndex)
+		names[i] = field.Name
+		rt = field.Type
+	}
+	return strings.Join(names, ".")
+}
+
+// checkRules applies field's rules to value in order and returns the first
+// failure.
+func checkRules(field fieldSchema, value reflect.Value) (FieldError, bool) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			break
+		}
+		value = value.Elem()
+	}
+	for _, rule := range field.validation {
+		if rule.name == "omitempty" {
+			if value.IsZero() {
+				return FieldError{}, true
+			}
+			continue
+		}
+		if !checkRule(rule, value) {
+			var failed any
+			if value.IsValid() && value.CanInterface() {
+				failed = value.Interface()
+			}
+			return FieldError{
+				Field:    field.goName,
+				Property: field.tagName,
+				Rule:     rule.name,
+				Param:    rule.param,
+				Value:    failed,
+			}, false
+		}
+	}
+	return FieldError{}, true
+}
+
+func checkRule(rule validationRule, value reflect.Value) bool {
+	if rule.name == "required" {
+		return !value.IsZero()
+	}
+	if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+		// Only required applies to unset optional values.
+		return true
+	}
+	switch rule.name {
+	case "min", "max":
+		limit, _ := strconv.ParseFloat(rule.param, 64)
+		size, ok := validationSize(value)
+		if !ok {
+			return false
+		}
+		if rule.name == "min" {
+			return size >= limit
+		}
+		return size <= limit
+	case "email":
+		if value.Kind() != reflect.String {
+			return false
+		}
+		address, err := mail.ParseAddress(value.String())
+		return err == nil && address.Name == "" && address.Address == value.String()
+	case "oneof":
+		options := strings.Split(rule.param, "|")
+		if value.Kind() == reflect.Slice || value.Kind() == reflect.Array {
+			for i := range value.Len() {
+				if !slices.Contains(options, fmt.Sprint(value.Index(i).Interface())) {
+					return false
+				}
+			}
+			return true
+		}
+		return slices.Contains(options, fmt.Sprint(value.Interface()))
+	}
+	return true
+}
+
+// validationSize returns what min and max compare: the value of numbers, the
+// number of characters of strings and the length of slices and maps.
+func validationSize(value reflect.Value) (float64, bool) {
+	switch value.Kind() { //nolint: exhaustive // Other kinds have no size
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), true
+	}
+	return 0, false
+}
+
+// validatorFor returns the configured Validator or the TagValidator.
+func validatorFor(config Config) Validator {
+	if config.Validator != nil {
+		return config.Validator
+	}
+	return TagValidator{}
+}
+
+// validateUpdates checks the properties Updates is about to write to models
+// of type rt: the values are copied into a zero model and its matching
+// fields are validated with the driver's PartialValidator. Values whose type
+// does not fit the field are left to the database to reject.
+func validateUpdates(db *GremlinDriver, rt reflect.Type, schema *typeSchema, properties map[string]any) error {
+	validator, ok := db.validator.(PartialValidator)
+	if !ok {
+		return nil
+	}
+	model := reflect.New(rt)
+	fields := make([]string, 0, len(properties))
+	for key, value := range properties {
+		field, ok := schema.mapFieldByTag(key)
+		if !ok {
+			continue
+		}
+		target := model.Elem().FieldByIndex(field.index)
+		if !assignValidated(target, value) {
+			continue
+		}
+		fields = append(fields, fieldPath(rt, field.index))
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	slices.Sort(fields)
+	return validator.ValidatePartial(model.Interface(), fields...)
+}
+
+// assignValidated sets target to value when it is assignable, or a number
+// convertible to target's number type.
+func assignValidated(target reflect.Value, value any) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch {
+	case rv.Type().AssignableTo(target.Type()):
+		target.Set(rv)
+	case isNumberKind(rv.Kind()) && isNumberKind(target.Kind()):
+		target.Set(rv.Convert(target.Type()))
+	default:
+		return false
+	}
+	return true
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
@@ -0,0 +1,197 @@
+package driver_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+type testValidatedUser struct {
+	gsmtypes.Vertex
+	Name     string   `json:"name"     gremlin:"name"     gsmvalidate:"required,max=10"`
+	Age      int      `json:"age"      gremlin:"age"      gsmvalidate:"min=0,max=150"`
+	Email    string   `json:"email"    gremlin:"email"    gsmvalidate:"omitempty,email"`
+	Role     string   `json:"role"     gremlin:"role"     gsmvalidate:"oneof=admin|member"`
+	Tags     []string `json:"tags"     gremlin:"tags"     gsmvalidate:"max=2"`
+	Nickname *string  `json:"nickname" gremlin:"nickname" gsmvalidate:"min=3"`
+	Bio      string   `json:"bio"      gremlin:"bio"`
+}
+
+type testBadlyValidatedUser struct {
+	gsmtypes.Vertex
+	Name string `json:"name" gremlin:"name" gsmvalidate:"requird"`
+}
+
+// recordingValidator records the values it is asked to validate.
+type recordingValidator struct {
+	values []any
+}
+
+func (v *recordingValidator) Validate(value any) error {
+	v.values = append(v.values, value)
+	return errors.New("rejected")
+}
+
+func validUser() testValidatedUser {
+	return testValidatedUser{Name: "Ann", Age: 30, Role: "member", Tags: []string{"a"}}
+}
+
+func TestTagValidator(t *testing.T) {
+	t.Parallel()
+	short := "al"
+	tests := []struct {
+		name      string
+		modify    func(*testValidatedUser)
+		wantRules []string
+	}{
+		{name: "Valid", modify: func(*testValidatedUser) {}},
+		{name: "Required", modify: func(u *testValidatedUser) { u.Name = "" }, wantRules: []string{"required"}},
+		{name: "MaxLength", modify: func(u *testValidatedUser) { u.Name = "Bartholomew" }, wantRules: []string{"max"}},
+		{name: "Range", modify: func(u *testValidatedUser) { u.Age = 151 }, wantRules: []string{"max"}},
+		{name: "OmitEmptyEmail", modify: func(u *testValidatedUser) { u.Email = "" }},
+		{name: "Email", modify: func(u *testValidatedUser) { u.Email = "ann@" }, wantRules: []string{"email"}},
+		{name: "OneOf", modify: func(u *testValidatedUser) { u.Role = "owner" }, wantRules: []string{"oneof"}},
+		{name: "SliceLength", modify: func(u *testValidatedUser) { u.Tags = []string{"a", "b", "c"} }, wantRules: []string{"max"}},
+		{name: "NilPointer", modify: func(u *testValidatedUser) { u.Nickname = nil }},
+		{name: "Pointer", modify: func(u *testValidatedUser) { u.Nickname = &short }, wantRules: []string{"min"}},
+		{
+			name: "CollectsAll",
+			modify: func(u *testValidatedUser) {
+				u.Name = ""
+				u.Age = -1
+			},
+			wantRules: []string{"required", "min"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				user := validUser()
+				tt.modify(&user)
+				err := driver.TagValidator{}.Validate(&user)
+				if len(tt.wantRules) == 0 {
+					if err != nil {
+						t.Fatalf("Expected no error, got %v", err)
+					}
+					return
+				}
+				var validationErrs driver.ValidationErrors
+				if !errors.As(err, &validationErrs) || !errors.Is(err, driver.ErrValidation) {
+					t.Fatalf("Expected ValidationErrors, got %v", err)
+				}
+				rules := make([]string, len(validationErrs))
+				for i, fieldErr := range validationErrs {
+					rules[i] = fieldErr.Rule
+				}
+				if !slices.Equal(rules, tt.wantRules) {
+					t.Errorf("Expected failed rules %v, got %v", tt.wantRules, rules)
+				}
+			},
+		)
+	}
+
+	t.Run(
+		"UnknownRule", func(t *testing.T) {
+			t.Parallel()
+			err := driver.TagValidator{}.Validate(testBadlyValidatedUser{Name: "Ann"})
+			if err == nil || !strings.Contains(err.Error(), `unknown gsmvalidate rule "requird"`) {
+				t.Errorf("Expected an unknown rule error, got %v", err)
+			}
+		},
+	)
+}
+
+func TestValidationOnWrite(t *testing.T) {
+	t.Parallel()
+
+	t.Run(
+		"CreateRejectsInvalidModel", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			user := validUser()
+			user.Age = 200
+			err := driver.Create(db, &user)
+			var validationErrs driver.ValidationErrors
+			if !errors.As(err, &validationErrs) || validationErrs[0].Property != "age" {
+				t.Fatalf("Expected an age validation error, got %v", err)
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"SaveValidatesUpdates", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+
+			user := validUser()
+			user.ID = "1"
+			user.Role = "owner"
+			if err := driver.Save(db, &user); !errors.Is(err, driver.ErrValidation) {
+				t.Errorf("Expected a validation error, got %v", err)
+			}
+			if len(server.requests) != 0 {
+				t.Errorf("Expected no requests, got %d", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"UpdatesValidatesOnlyUpdatedProperties", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+			query := func() *driver.Query[testValidatedUser] {
+				return driver.Model[testValidatedUser](db).Where("name", comparator.EQ, "Ann")
+			}
+
+			// name is required, but Updates leaves it alone.
+			if err := query().Updates(map[string]any{"bio": "hi", "age": int64(40)}); err != nil {
+				t.Fatal(err)
+			}
+			err := query().Updates(map[string]any{"age": 200, "role": "owner"})
+			var validationErrs driver.ValidationErrors
+			if !errors.As(err, &validationErrs) || len(validationErrs) != 2 {
+				t.Fatalf("Expected age and role validation errors, got %v", err)
+			}
+			if len(server.requests) != 1 {
+				t.Errorf("Expected only the valid update to run, got %d requests", len(server.requests))
+			}
+		},
+	)
+
+	t.Run(
+		"CustomValidator", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			validator := &recordingValidator{}
+			db := driver.NewScriptDriverForTest(driver.Config{Validator: validator}, server.submit)
+
+			user := validUser()
+			if err := driver.Create(db, &user); err == nil || err.Error() != "rejected" {
+				t.Errorf("Expected the custom validator's error, got %v", err)
+			}
+			if len(validator.values) != 1 || validator.values[0] != &user {
+				t.Errorf("Expected the model to be validated once, got %v", validator.values)
+			}
+			// Validators without ValidatePartial do not check Updates.
+			err := driver.Model[testValidatedUser](db).
+				Where("name", comparator.EQ, "Ann").
+				Updates(map[string]any{"age": 200})
+			if err != nil {
+				t.Errorf("Expected Updates to skip validation, got %v", err)
+			}
+		},
+	)
+}
@@ -0,0 +1,224 @@
+package driver
+
+import (
+	"fmt"
+	"reflect"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// mutatingSteps are the steps rejected by ReadOnly views.
+var mutatingSteps = map[string]bool{
+	"addV": true, "addE": true, "property": true, "drop": true, "mergeV": true, "mergeE": true,
+}
+
+// vertexSteps and edgeSteps are the steps followed by the injected vertex
+// and edge filters.
+var (
+	vertexSteps = map[string]bool{
+		"V": true, "out": true, "in": true, "both": true,
+		"outV": true, "inV": true, "otherV": true, "bothV": true,
+	}
+	edgeSteps = map[string]bool{"E": true, "outE": true, "inE": true, "bothE": true}
+	// adjacencySteps map the steps that skip over edges to the edge and
+	// vertex steps they are expanded into when edges are filtered.
+	adjacencySteps = map[string][2]string{
+		"out":  {"outE", "inV"},
+		"in":   {"inE", "outV"},
+		"both": {"bothE", "otherV"},
+	}
+)
+
+// ReadOnly returns a view of the driver that cannot modify the graph.
+// Create, Save, Updates, Delete and any traversal with a mutating step fail
+// with ErrReadOnly before they are sent, and dialects supporting
+// FeatureStrategies also apply TinkerPop's ReadOnlyStrategy on the server.
+//
+// The view shares the connections of driver, so closing it is a no-op;
+// close driver instead.
+func (driver *GremlinDriver) ReadOnly() *GremlinDriver {
+	view := driver.view()
+	view.readOnly = true
+	if driver.dialect.Supports(FeatureStrategies) {
+		view.g = driver.g.WithStrategies(gremlingo.ReadOnlyStrategy())
+	}
+	return view
+}
+
+// WithSubgraph returns a view of the driver that only sees the vertices
+// matching vertexFilter and the edges matching edgeFilter, anonymous filter
+// traversals such as gremlingo.T__.Has("published", true). Either filter may
+// be nil to keep all vertices or edges.
+//
+// The subgraph is enforced with TinkerPop's SubgraphStrategy, or on dialects
+// without FeatureStrategies by injecting where() steps after every step that
+// reaches vertices or edges.
+//
+// The view shares the connections of driver, so closing it is a no-op;
+// close driver instead.
+func (driver *GremlinDriver) WithSubgraph(vertexFilter, edgeFilter *gremlingo.GraphTraversal) *GremlinDriver {
+	view := driver.view()
+	if driver.dialect.Supports(FeatureStrategies) {
+		view.g = driver.g.WithStrategies(
+			gremlingo.SubgraphStrategy(
+				gremlingo.SubgraphStrategyConfig{Vertices: vertexFilter, Edges: edgeFilter},
+			),
+		)
+		return view
+	}
+	var subgraph injectedSteps
+	if vertexFilter != nil {
+		subgraph.vertexFilters = []step{{operator: "where", args: []any{vertexFilter}}}
+	}
+	if edgeFilter != nil {
+		subgraph.edgeFilters = []step{{operator: "where", args: []any{edgeFilter}}}
+	}
+	view.injected = view.injected.with(subgraph)
+	return view
+}
+
+// view returns a copy of driver sharing its connections.
+func (driver *GremlinDriver) view() *GremlinDriver {
+	view := *driver
+	view.isView = true
+	return &view
+}
+
+// prepare checks traversal against a ReadOnly view and the dialect's
+// features and returns it with the steps injected by the driver's views, if
+// any. It fails for gremlingo versions whose bytecode layout readField cannot
+// read. The traversal itself is left untouched so that retries do not inject
+// the steps twice.
+func (driver *GremlinDriver) prepare(traversal *gremlingo.GraphTraversal) (*gremlingo.GraphTraversal, error) {
+	if errBytecodeLayout != nil {
+		return nil, errBytecodeLayout
+	}
+	if driver.readOnly {
+		if operator := mutatingStep(traversal.Bytecode); operator != "" {
+			return nil, fmt.Errorf("%w: %s() modifies the graph", ErrReadOnly, operator)
+		}
+	}
+	if step := unsupportedStep(traversal.Bytecode, driver.dialect); step != "" {
+		return nil, fmt.Errorf("%s is %w by %s", step, ErrUnsupported, driver.dialect.Name())
+	}
+	if driver.injected == nil {
+		return traversal, nil
+	}
+	prepared := *traversal.Traversal
+	prepared.Bytecode = driver.injected.rewrite(traversal.Bytecode, true)
+	return &gremlingo.GraphTraversal{Traversal: &prepared}, nil
+}
+
+// mutatingStep returns the first step of bytecode, including its child
+// traversals, that modifies the graph, or "" when there is none.
+func mutatingStep(bytecode *gremlingo.Bytecode) string {
+	steps := readField(reflect.ValueOf(bytecode).Elem(), "stepInstructions")
+	for i := range steps.Len() {
+		operator, args := readInstruction(steps.Index(i))
+		if mutatingSteps[operator] {
+			return operator
+		}
+		for _, arg := range args {
+			if child, ok := arg.(*gremlingo.Bytecode); ok {
+				if operator := mutatingStep(child); operator != "" {
+					return operator
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// step is a bytecode step instruction.
+type step struct {
+	operator string
+	args     []any
+}
+
+// injectedSteps are the steps views inject into every traversal on
+// dialects without FeatureStrategies.
+type injectedSteps struct {
+	// vertexFilters follow every step in vertexSteps
+	vertexFilters []step
+	// edgeFilters follow every step in edgeSteps
+	edgeFilters []step
+	// writes follow every addV and addE, after their from() and to()
+	// modulators
+	writes []step
+}
+
+// with returns the steps of s followed by those of other, leaving s
+// unchanged. s may be nil.
+func (s *injectedSteps) with(other injectedSteps) *injectedSteps {
+	combined := &other
+	if s != nil {
+		combined = &injectedSteps{
+			vertexFilters: append(append([]step(nil), s.vertexFilters...), other.vertexFilters...),
+			edgeFilters:   append(append([]step(nil), s.edgeFilters...), other.edgeFilters...),
+			writes:        append(append([]step(nil), s.writes...), other.writes...),
+		}
+	}
+	return combined
+}
+
+// rewrite returns a copy of bytecode with the injected steps added,
+// including in child traversals. When edges are filtered, out(), in() and
+// both() are expanded to pass through the edge filters. Child traversals carry no source
+// instructions, so only the root copies them. The arguments are already
+// converted, so AddSource and AddStep cannot fail.
+func (s *injectedSteps) rewrite(bytecode *gremlingo.Bytecode, root bool) *gremlingo.Bytecode {
+	rewritten := gremlingo.NewBytecode(nil)
+	bytecodeValue := reflect.ValueOf(bytecode).Elem()
+	if root {
+		sources := readField(bytecodeValue, "sourceInstructions")
+		for i := range sources.Len() {
+			operator, args := readInstruction(sources.Index(i))
+			_ = rewritten.AddSource(operator, args...)
+		}
+	}
+	addSteps := func(steps []step) {
+		for _, injected := range steps {
+			_ = rewritten.AddStep(injected.operator, injected.args...)
+		}
+	}
+	// addedElement is set after addV and addE until their from() and to()
+	// modulators have been copied.
+	addedElement := false
+	steps := readField(bytecodeValue, "stepInstructions")
+	for i := range steps.Len() {
+		operator, args := readInstruction(steps.Index(i))
+		if addedElement && operator != "from" && operator != "to" {
+			addSteps(s.writes)
+			addedElement = false
+		}
+		for j, arg := range args {
+			if child, ok := arg.(*gremlingo.Bytecode); ok {
+				args[j] = s.rewrite(child, false)
+			}
+		}
+		if expanded, ok := adjacencySteps[operator]; ok && len(s.edgeFilters) > 0 {
+			_ = rewritten.AddStep(expanded[0], args...)
+			addSteps(s.edgeFilters)
+			operator, args = expanded[1], nil
+		}
+		_ = rewritten.AddStep(operator, args...)
+		switch {
+		case vertexSteps[operator]:
+			addSteps(s.vertexFilters)
+		case edgeSteps[operator]:
+			addSteps(s.edgeFilters)
+		}
+		addedElement = addedElement || operator == "addV" || operator == "addE"
+	}
+	if addedElement {
+		addSteps(s.writes)
+	}
+	return rewritten
+}
+
+// readInstruction returns the operator and a copy of the arguments of a
+// bytecode instruction.
+func readInstruction(instruction reflect.Value) (string, []any) {
+	args, _ := readField(instruction, "arguments").Interface().([]any)
+	return readField(instruction, "operator").String(), append([]any(nil), args...)
+}
@@ -0,0 +1,125 @@
+package driver_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+func TestReadOnly(t *testing.T) {
+	t.Parallel()
+	writes := []struct {
+		name string
+		run  func(db *driver.GremlinDriver) error
+	}{
+		{
+			name: "Create",
+			run:  func(db *driver.GremlinDriver) error { return driver.Create(db, &testTopic{Title: "graphs"}) },
+		},
+		{
+			name: "Save",
+			run: func(db *driver.GremlinDriver) error {
+				topic := testTopic{Title: "graphs"}
+				topic.ID = "1"
+				return driver.Save(db, &topic)
+			},
+		},
+		{
+			name: "Updates",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testTopic](db).
+					Where("title", comparator.EQ, "graphs").
+					Updates(map[string]any{"title": "trees"})
+			},
+		},
+		{
+			name: "Delete",
+			run: func(db *driver.GremlinDriver) error {
+				return driver.Model[testTopic](db).Where("title", comparator.EQ, "graphs").Delete()
+			},
+		},
+		{
+			name: "ChildTraversal",
+			run: func(db *driver.GremlinDriver) error {
+				return db.Iterate(db.G().V("1").SideEffect(gremlingo.T__.Properties("title").Drop()))
+			},
+		},
+	}
+	for _, dialect := range []driver.Dialect{driver.TinkerGraphDialect{}, driver.CosmosDBDialect{}} {
+		for _, tt := range writes {
+			t.Run(
+				dialect.Name()+"/"+tt.name, func(t *testing.T) {
+					t.Parallel()
+					server := &recordedServer{responses: [][]any{{map[any]any{"id": "1"}}}}
+					db := driver.NewScriptDriverForTest(driver.Config{Dialect: dialect}, server.submit)
+					if err := tt.run(db.ReadOnly()); !errors.Is(err, driver.ErrReadOnly) {
+						t.Errorf("Expected ErrReadOnly, got %v", err)
+					}
+					if len(server.requests) != 0 {
+						t.Errorf("Expected no requests, got %s", server.requests[0].script)
+					}
+				},
+			)
+		}
+	}
+
+	t.Run(
+		"ReadsUseReadOnlyStrategy", func(t *testing.T) {
+			t.Parallel()
+			server := &recordedServer{responses: [][]any{{}}}
+			db := driver.NewScriptDriverForTest(driver.Config{}, server.submit)
+			if _, err := driver.Model[testTopic](db.ReadOnly()).Find(); err != nil {
+				t.Fatal(err)
+			}
+			if script := server.requests[0].script; !strings.HasPrefix(script, "g.withStrategies(new ReadOnlyStrategy()).V()") {
+				t.Errorf("Expected the ReadOnlyStrategy, got %s", script)
+			}
+		},
+	)
+}
+
+func TestWithSubgraph(t *testing.T) {
+	t.Parallel()
+	vertices := gremlingo.T__.Has("published", true)
+	edges := gremlingo.T__.Has("weight", gremlingo.P.Gt(1))
+	tests := []struct {
+		name    string
+		dialect driver.Dialect
+		want    string
+	}{
+		{
+			name:    "SubgraphStrategy",
+			dialect: driver.TinkerGraphDialect{},
+			want: `g.withStrategies(new SubgraphStrategy(edges: __.has("weight", P.gt(1)), ` +
+				`vertices: __.has("published", true))).V().hasLabel("test_person")`,
+		},
+		{
+			name:    "InjectedFilters",
+			dialect: driver.CosmosDBDialect{},
+			want: `g.V().where(__.has("published", true)).hasLabel("test_person")` +
+				`.local(__.union(__.valueMap(true, "id", "last_modified", "created_at", "name"), ` +
+				`__.project("Topics").by(__.outE("subscribed").where(__.has("weight", P.gt(1)))` +
+				`.inV().where(__.has("published", true)).hasLabel("test_topic")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				server := &recordedServer{responses: [][]any{{}}}
+				db := driver.NewScriptDriverForTest(driver.Config{Dialect: tt.dialect}, server.submit)
+				_, err := driver.Model[testPerson](db.WithSubgraph(vertices, edges)).Preload("Topics").Find()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if script := server.requests[0].resolvedScript(); !strings.HasPrefix(script, tt.want) {
+					t.Errorf("Expected script to start with %s, got %s", tt.want, script)
+				}
+			},
+		)
+	}
+}
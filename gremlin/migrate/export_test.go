@@ -0,0 +1,11 @@
+package migrate
+
+import "github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+
+func BatchSizeForTest(dialect driver.Dialect, batchSize int) int {
+	return batchSizeFor(dialect, batchSize)
+}
+
+func (m *Migrator) AcquireLockForTest() error { return m.acquireLock() }
+
+func (m *Migrator) ReleaseLockForTest() error { return m.releaseLock() }
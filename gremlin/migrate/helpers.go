@@ -0,0 +1,251 @@
+package migrate
+
+import (
+	"fmt"
+	"slices"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+)
+
+const defaultBatchSize = 1000
+
+// batchSizeFor returns batchSize capped at the dialect's maximum batch size,
+// or that maximum when batchSize is not positive.
+func batchSizeFor(dialect driver.Dialect, batchSize int) int {
+	maxBatch := dialect.MaxBatchSize()
+	switch {
+	case batchSize > 0 && (maxBatch <= 0 || batchSize <= maxBatch):
+		return batchSize
+	case maxBatch > 0:
+		return maxBatch
+	}
+	return defaultBatchSize
+}
+
+// RenameProperty renames the from property of every vertex labelled label
+// to to, batchSize vertices per request, and returns the number of vertices
+// changed. batchSize is capped at the dialect's maximum batch size, which
+// a batchSize of zero selects.
+func RenameProperty(db *driver.GremlinDriver, label, from, to string, batchSize int) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	return runBatches(
+		db, batchSizeFor(db.Dialect(), batchSize), func(limit int) *gremlingo.GraphTraversal {
+			return db.G().V().HasLabel(label).Has(from).Limit(limit).As("v").
+				SideEffect(copyValues(db, from, to)).
+				SideEffect(anonymousTraversal.Properties(from).Drop()).
+				Count()
+		},
+	)
+}
+
+// CopyProperty copies the from property of every vertex labelled label
+// into to, skipping vertices that already have to, batchSize vertices per
+// request. It returns the number of vertices changed.
+func CopyProperty(db *driver.GremlinDriver, label, from, to string, batchSize int) (int, error) {
+	return runBatches(
+		db, batchSizeFor(db.Dialect(), batchSize), func(limit int) *gremlingo.GraphTraversal {
+			return db.G().V().HasLabel(label).Has(from).HasNot(to).Limit(limit).As("v").
+				SideEffect(copyValues(db, from, to)).
+				Count()
+		},
+	)
+}
+
+// copyValues writes every value of the from property of the vertex labelled
+// v to to, with the dialect's slice cardinality when there is more than one.
+func copyValues(db *driver.GremlinDriver, from, to string) *gremlingo.GraphTraversal {
+	write := func(cardinality any) *gremlingo.GraphTraversal {
+		return anonymousTraversal.Properties(from).As("p").
+			Select("v").
+			Property(cardinality, to, anonymousTraversal.Select("p").Value())
+	}
+	return anonymousTraversal.Choose(
+		anonymousTraversal.Properties(from).Count().Is(gremlingo.P.Gt(1)),
+		write(db.Dialect().SliceCardinality()),
+		write(gremlingo.Cardinality.Single),
+	)
+}
+
+// runBatches runs the traversal built by batch until it reports no changed
+// vertices, and returns the total.
+func runBatches(
+	db *driver.GremlinDriver,
+	batchSize int,
+	batch func(limit int) *gremlingo.GraphTraversal,
+) (int, error) {
+	total := 0
+	for {
+		results, err := db.ToList(batch(batchSize))
+		if err != nil {
+			return total, err
+		}
+		if len(results) == 0 {
+			return total, nil
+		}
+		changed, err := results[0].GetInt()
+		if err != nil {
+			return total, err
+		}
+		if changed == 0 {
+			return total, nil
+		}
+		total += changed
+	}
+}
+
+// RelabelVertices moves every vertex labelled from to label to, batchSize
+// vertices per request, and returns the number moved. Gremlin cannot change
+// a vertex's label, so each vertex is replaced by a copy with the new label,
+// its properties and its edges, and the copy gets a new id.
+func RelabelVertices(db *driver.GremlinDriver, from, to string, batchSize int) (int, error) {
+	if from == to {
+		return 0, nil
+	}
+	batchSize = batchSizeFor(db.Dialect(), batchSize)
+	total := 0
+	for {
+		ids, err := db.ToList(db.G().V().HasLabel(from).Limit(batchSize).Id())
+		if err != nil {
+			return total, err
+		}
+		if len(ids) == 0 {
+			return total, nil
+		}
+		for _, id := range ids {
+			if err = relabelVertex(db, id.GetInterface(), to); err != nil {
+				return total, fmt.Errorf("failed to relabel vertex %v: %w", id.GetInterface(), err)
+			}
+			total++
+		}
+	}
+}
+
+// relabeledEdge is an edge of a vertex being relabeled.
+type relabeledEdge struct {
+	label      string
+	other      any
+	properties map[any]any
+}
+
+// relabelVertex replaces the vertex id with a copy labelled label in a
+// single traversal.
+func relabelVertex(db *driver.GremlinDriver, id any, label string) error {
+	properties, err := vertexProperties(db, id)
+	if err != nil {
+		return err
+	}
+	outEdges, err := vertexEdges(db, db.G().V(id).OutE(), anonymousTraversal.InV().Id())
+	if err != nil {
+		return err
+	}
+	inEdges, err := vertexEdges(db, db.G().V(id).InE(), anonymousTraversal.OutV().Id())
+	if err != nil {
+		return err
+	}
+
+	query := db.G().AddV(label)
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		values := properties[key]
+		if len(values) == 1 {
+			query = query.Property(gremlingo.Cardinality.Single, key, values[0])
+			continue
+		}
+		for _, value := range values {
+			query = query.Property(db.Dialect().SliceCardinality(), key, value)
+		}
+	}
+	query = query.As("new")
+	for _, edge := range outEdges {
+		target := anonymousTraversal.V(edge.other)
+		if edge.other == id {
+			// Self loops appear as both out and in edges; copy them once.
+			target = anonymousTraversal.Select("new")
+		}
+		query = query.SideEffect(withEdgeProperties(anonymousTraversal.AddE(edge.label).To(target), edge))
+	}
+	for _, edge := range inEdges {
+		if edge.other == id {
+			continue
+		}
+		query = query.SideEffect(
+			withEdgeProperties(anonymousTraversal.AddE(edge.label).From(anonymousTraversal.V(edge.other)), edge),
+		)
+	}
+	return db.Iterate(query.SideEffect(anonymousTraversal.V(id).Drop()))
+}
+
+// vertexProperties returns the values of every property of vertex id.
+func vertexProperties(db *driver.GremlinDriver, id any) (map[string][]any, error) {
+	results, err := db.ToList(
+		db.G().V(id).Properties().
+			Project("key", "value").
+			By(anonymousTraversal.Key()).
+			By(anonymousTraversal.Value()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	properties := make(map[string][]any)
+	for _, result := range results {
+		property, ok := result.GetInterface().(map[any]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected property result %T", result.GetInterface())
+		}
+		key := fmt.Sprint(property["key"])
+		properties[key] = append(properties[key], property["value"])
+	}
+	return properties, nil
+}
+
+// vertexEdges returns the label, other end and properties of the edges
+// traversed by edges.
+func vertexEdges(
+	db *driver.GremlinDriver,
+	edges *gremlingo.GraphTraversal,
+	otherID *gremlingo.GraphTraversal,
+) ([]relabeledEdge, error) {
+	results, err := db.ToList(
+		edges.Project("label", "other", "properties").
+			By(anonymousTraversal.Label()).
+			By(otherID).
+			By(anonymousTraversal.ValueMap()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	relabeled := make([]relabeledEdge, 0, len(results))
+	for _, result := range results {
+		edge, ok := result.GetInterface().(map[any]any)
+		if !ok {
+			return nil, fmt.Errorf("unexpected edge result %T", result.GetInterface())
+		}
+		properties, _ := edge["properties"].(map[any]any)
+		relabeled = append(relabeled, relabeledEdge{
+			label:      fmt.Sprint(edge["label"]),
+			other:      edge["other"],
+			properties: properties,
+		})
+	}
+	return relabeled, nil
+}
+
+// withEdgeProperties sets edge's properties on the edge added by addE.
+func withEdgeProperties(addE *gremlingo.GraphTraversal, edge relabeledEdge) *gremlingo.GraphTraversal {
+	keys := make([]string, 0, len(edge.properties))
+	for key := range edge.properties {
+		keys = append(keys, fmt.Sprint(key))
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		addE = addE.Property(key, edge.properties[key])
+	}
+	return addE
+}
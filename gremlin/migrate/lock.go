@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	gremlingo "github.com/apache/tinkerpop/gremlin-go/v3/driver"
+)
+
+// LockLabel is the label of the vertex held by the runner applying
+// migrations.
+const LockLabel = "gsm_migration_lock"
+
+// ErrLocked is returned by Up and Down while another runner holds the
+// migration lock.
+var ErrLocked = errors.New("migrations are locked")
+
+var anonymousTraversal = gremlingo.T__
+
+// withLock runs fn while holding the migration lock.
+func (m *Migrator) withLock(fn func() error) error {
+	if err := m.acquireLock(); err != nil {
+		return err
+	}
+	err := fn()
+	if releaseErr := m.releaseLock(); releaseErr != nil {
+		err = errors.Join(err, fmt.Errorf("failed to release migration lock: %w", releaseErr))
+	}
+	return err
+}
+
+// acquireLock takes the lock vertex in one conditional traversal: it takes
+// over an expired lock, leaves a live one to its holder, and creates the
+// vertex when there is none. Runners that create a lock vertex at the same
+// moment may both see more than one, and then both give up; holdLock catches
+// a runner that only saw its own.
+func (m *Migrator) acquireLock() error {
+	now := m.db.Now()
+	err := m.db.Iterate(
+		m.db.G().V().HasLabel(LockLabel).Fold().Coalesce(
+			anonymousTraversal.Unfold().
+				Has("expires_at", gremlingo.P.Lt(now)).
+				Property(gremlingo.Cardinality.Single, "owner", m.owner).
+				Property(gremlingo.Cardinality.Single, "expires_at", now.Add(m.lockTTL)),
+			anonymousTraversal.Unfold(),
+			anonymousTraversal.AddV(LockLabel).
+				Property(gremlingo.Cardinality.Single, "owner", m.owner).
+				Property(gremlingo.Cardinality.Single, "expires_at", now.Add(m.lockTTL)),
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return m.checkLock()
+}
+
+// holdLock extends the lock and checks this runner still holds it alone,
+// before each migration is applied.
+func (m *Migrator) holdLock() error {
+	err := m.db.Iterate(
+		m.db.G().V().HasLabel(LockLabel).
+			Has("owner", m.owner).
+			Property(gremlingo.Cardinality.Single, "expires_at", m.db.Now().Add(m.lockTTL)),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to extend migration lock: %w", err)
+	}
+	return m.checkLock()
+}
+
+// checkLock returns ErrLocked, dropping this runner's lock vertex if any,
+// unless this runner owns the only lock vertex.
+func (m *Migrator) checkLock() error {
+	owners, err := m.lockOwners()
+	if err != nil {
+		return err
+	}
+	if !slices.Equal(owners, []string{m.owner}) {
+		if slices.Contains(owners, m.owner) {
+			_ = m.releaseLock()
+		}
+		return fmt.Errorf("%w by %v", ErrLocked, owners)
+	}
+	return nil
+}
+
+// releaseLock drops the lock vertex if this runner holds it.
+func (m *Migrator) releaseLock() error {
+	return m.db.Iterate(m.db.G().V().HasLabel(LockLabel).Has("owner", m.owner).Drop())
+}
+
+// lockOwners returns the owners of every lock vertex.
+func (m *Migrator) lockOwners() ([]string, error) {
+	results, err := m.db.ToList(m.db.G().V().HasLabel(LockLabel).Values("owner"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration lock: %w", err)
+	}
+	owners := make([]string, 0, len(results))
+	for _, result := range results {
+		owners = append(owners, result.GetString())
+	}
+	return owners, nil
+}
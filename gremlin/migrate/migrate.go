@@ -0,0 +1,328 @@
+// Package migrate applies versioned data migrations to a graph. Migrations
+// are Go functions registered with Register, applied in version order by a
+// Migrator and recorded as gsm_migration vertices, with a checksum and the
+// time they were applied, so each runs once.
+//
+// The migration lock is best effort. Gremlin servers without transactions
+// cannot take it atomically, so runners that race for it are caught by
+// checking it is held alone before each migration, and a runner whose
+// migration outlasts Config.LockTTL can lose the lock to another while it
+// is still running.
+package migrate
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+	appLogger "github.com/jbrusegaard/graph-struct-manager/log"
+)
+
+// MigrationLabel is the label of the vertices recording applied migrations.
+const MigrationLabel = "gsm_migration"
+
+const defaultLockTTL = 15 * time.Minute
+
+var (
+	// ErrChecksumMismatch is returned by Up when the migration registered
+	// under an applied version has a different checksum than the one
+	// recorded: its name or Checksum changed, or the version was reused.
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	// ErrNoDown is returned by Down when the last applied migration has no
+	// Down function.
+	ErrNoDown = errors.New("migration has no down function")
+	// ErrNothingToRollBack is returned by Down when no registered migration
+	// has been applied.
+	ErrNothingToRollBack = errors.New("no applied migration to roll back")
+)
+
+// Migration is a versioned change to the graph.
+type Migration struct {
+	// Version orders migrations. Versions that are all digits, such as
+	// 20260102150405, compare numerically; others compare as strings.
+	Version string
+	// Name describes the migration in logs and in its record.
+	Name string
+	// Checksum identifies the migration's contents, such as a hash of its
+	// source or a revision number. Go functions cannot be hashed, so
+	// change it whenever Up or Down change to have Up detect edits to an
+	// applied migration.
+	Checksum string
+	// Up applies the migration.
+	Up func(db *driver.GremlinDriver) error
+	// Down reverts Up. It may be nil for irreversible migrations.
+	Down func(db *driver.GremlinDriver) error
+}
+
+// checksum is recorded with the migration and compared by Up, so renaming
+// an applied migration, reusing its version or changing its Checksum is
+// detected.
+func (m Migration) checksum() string {
+	sum := sha256.Sum256([]byte(m.Version + "\x00" + m.Name + "\x00" + m.Checksum))
+	return hex.EncodeToString(sum[:])
+}
+
+var (
+	registryMu sync.Mutex
+	registry   []Migration
+)
+
+// Register adds m to the migrations New applies by default, typically from
+// an init function next to the migration. It panics if m has no version or
+// Up function, or if its version is already registered.
+//
+//	func init() {
+//		migrate.Register(migrate.Migration{
+//			Version:  "20260102150405",
+//			Name:     "rename user fullname to name",
+//			Checksum: "1",
+//			Up: func(db *driver.GremlinDriver) error {
+//				_, err := migrate.RenameProperty(db, "user", "fullname", "name", 0)
+//				return err
+//			},
+//		})
+//	}
+func Register(m Migration) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if m.Version == "" || m.Up == nil {
+		panic("migrate: Register requires a version and an Up function")
+	}
+	for _, registered := range registry {
+		if registered.Version == m.Version {
+			panic(fmt.Sprintf("migrate: version %s is registered twice", m.Version))
+		}
+	}
+	registry = append(registry, m)
+}
+
+// Registered returns the registered migrations in version order.
+func Registered() []Migration {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	return sortMigrations(registry)
+}
+
+// Config configures a Migrator.
+type Config struct {
+	// Migrations to apply instead of the registered ones.
+	Migrations []Migration
+	// LockTTL is how long a runner holds the migration lock before another
+	// runner may take it over, in case the holder crashed (default 15m).
+	LockTTL time.Duration
+	// Owner identifies this runner in the lock (default host:pid).
+	Owner string
+}
+
+// Migrator applies migrations to a graph.
+type Migrator struct {
+	db         *driver.GremlinDriver
+	migrations []Migration
+	lockTTL    time.Duration
+	owner      string
+	logger     *log.Logger
+}
+
+// New returns a Migrator for db. Without a config it applies the registered
+// migrations.
+func New(db *driver.GremlinDriver, config ...Config) (*Migrator, error) {
+	var cfg Config
+	if len(config) > 0 {
+		cfg = config[0]
+	}
+	migrations := cfg.Migrations
+	if migrations == nil {
+		migrations = Registered()
+	}
+	migrations = sortMigrations(migrations)
+	for i, m := range migrations {
+		if m.Version == "" || m.Up == nil {
+			return nil, fmt.Errorf("migration %q needs a version and an Up function", m.Name)
+		}
+		if i > 0 && migrations[i-1].Version == m.Version {
+			return nil, fmt.Errorf("migration version %s is used twice", m.Version)
+		}
+	}
+	migrator := &Migrator{
+		db:         db,
+		migrations: migrations,
+		lockTTL:    cmp.Or(cfg.LockTTL, defaultLockTTL),
+		owner:      cfg.Owner,
+		logger:     appLogger.InitializeLogger(),
+	}
+	if migrator.owner == "" {
+		host, _ := os.Hostname()
+		migrator.owner = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	return migrator, nil
+}
+
+// migrationRecord is the gsm_migration vertex recording an applied
+// migration.
+type migrationRecord struct {
+	gsmtypes.Vertex
+	Version   string    `json:"version"    gremlin:"version"`
+	Name      string    `json:"name"       gremlin:"name"`
+	Checksum  string    `json:"checksum"   gremlin:"checksum"`
+	AppliedAt time.Time `json:"applied_at" gremlin:"applied_at"`
+}
+
+func (r *migrationRecord) Label() string { return MigrationLabel }
+
+// Status describes a migration known to the Migrator or the graph.
+type Status struct {
+	Version string
+	Name    string
+	// Applied reports whether the migration is recorded in the graph, and
+	// AppliedAt when.
+	Applied   bool
+	AppliedAt time.Time
+	// Registered is false for migrations recorded in the graph that the
+	// Migrator does not know.
+	Registered bool
+	// Modified reports an applied migration whose checksum no longer
+	// matches its record.
+	Modified bool
+}
+
+// Up applies every pending migration in version order, recording each one
+// as it succeeds. It stops at the first failure.
+func (m *Migrator) Up() error {
+	return m.withLock(func() error {
+		records, err := m.records()
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if record, ok := records[migration.Version]; ok {
+				if record.Checksum != migration.checksum() {
+					return fmt.Errorf(
+						"%w: %s was applied as %q", ErrChecksumMismatch, migration.Version, record.Name,
+					)
+				}
+				continue
+			}
+			if err = m.holdLock(); err != nil {
+				return err
+			}
+			m.logger.Infof("Applying migration %s %s", migration.Version, migration.Name)
+			if err = migration.Up(m.db); err != nil {
+				return fmt.Errorf("migration %s %s: %w", migration.Version, migration.Name, err)
+			}
+			record := migrationRecord{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.checksum(),
+				AppliedAt: m.db.Now(),
+			}
+			if err = driver.Create(m.db, &record); err != nil {
+				return fmt.Errorf("failed to record migration %s: %w", migration.Version, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Down reverts the most recently applied migration and removes its record.
+func (m *Migrator) Down() error {
+	return m.withLock(func() error {
+		records, err := m.records()
+		if err != nil {
+			return err
+		}
+		for _, migration := range slices.Backward(m.migrations) {
+			if _, ok := records[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("%w: %s %s", ErrNoDown, migration.Version, migration.Name)
+			}
+			if err = m.holdLock(); err != nil {
+				return err
+			}
+			m.logger.Infof("Reverting migration %s %s", migration.Version, migration.Name)
+			if err = migration.Down(m.db); err != nil {
+				return fmt.Errorf("migration %s %s: %w", migration.Version, migration.Name, err)
+			}
+			err = driver.Model[migrationRecord](m.db).
+				Where("version", comparator.EQ, migration.Version).
+				Delete()
+			if err != nil {
+				return fmt.Errorf("failed to remove record of migration %s: %w", migration.Version, err)
+			}
+			return nil
+		}
+		return ErrNothingToRollBack
+	})
+}
+
+// Status lists the registered and recorded migrations in version order.
+func (m *Migrator) Status() ([]Status, error) {
+	records, err := m.records()
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name, Registered: true}
+		if record, ok := records[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = record.AppliedAt
+			status.Modified = record.Checksum != migration.checksum()
+			delete(records, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range records {
+		statuses = append(statuses, Status{
+			Version:   record.Version,
+			Name:      record.Name,
+			Applied:   true,
+			AppliedAt: record.AppliedAt,
+		})
+	}
+	slices.SortFunc(statuses, func(a, b Status) int { return compareVersions(a.Version, b.Version) })
+	return statuses, nil
+}
+
+// records returns the applied migration records by version.
+func (m *Migrator) records() (map[string]migrationRecord, error) {
+	found, err := driver.Model[migrationRecord](m.db).AllowGlobal().Find()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration records: %w", err)
+	}
+	records := make(map[string]migrationRecord, len(found))
+	for _, record := range found {
+		records[record.Version] = record
+	}
+	return records, nil
+}
+
+// sortMigrations returns a copy of migrations in version order.
+func sortMigrations(migrations []Migration) []Migration {
+	sorted := slices.Clone(migrations)
+	slices.SortStableFunc(sorted, func(a, b Migration) int { return compareVersions(a.Version, b.Version) })
+	return sorted
+}
+
+// compareVersions compares all-digit versions numerically and any others
+// as strings.
+func compareVersions(a, b string) int {
+	x, errA := strconv.ParseUint(a, 10, 64)
+	y, errB := strconv.ParseUint(b, 10, 64)
+	if errA == nil && errB == nil {
+		return cmp.Compare(x, y)
+	}
+	return cmp.Compare(a, b)
+}
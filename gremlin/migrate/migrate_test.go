@@ -0,0 +1,309 @@
+package migrate_test
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jbrusegaard/graph-struct-manager/comparator"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/driver"
+	"github.com/jbrusegaard/graph-struct-manager/gremlin/migrate"
+	"github.com/jbrusegaard/graph-struct-manager/gsmtypes"
+)
+
+const DbURL = "ws://localhost:8182"
+
+type testMigratedUser struct {
+	gsmtypes.Vertex
+	Name  string   `json:"name"  gremlin:"name"`
+	Email string   `json:"email" gremlin:"email"`
+	Tags  []string `json:"tags"  gremlin:"tags"`
+}
+
+func noop(*driver.GremlinDriver) error { return nil }
+
+func openDB(t *testing.T) *driver.GremlinDriver {
+	t.Helper()
+	db, err := driver.Open(DbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(db.Close)
+	return db
+}
+
+// uniqueVersion returns a version no other test run has recorded.
+func uniqueVersion(offset int) string {
+	return fmt.Sprint(time.Now().UnixNano() + int64(offset))
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name       string
+		migrations []migrate.Migration
+		wantErr    bool
+	}{
+		{
+			name: "Valid",
+			migrations: []migrate.Migration{
+				{Version: "2", Name: "second", Up: noop},
+				{Version: "10", Name: "tenth", Up: noop},
+			},
+		},
+		{
+			name: "DuplicateVersion",
+			migrations: []migrate.Migration{
+				{Version: "1", Name: "first", Up: noop},
+				{Version: "1", Name: "again", Up: noop},
+			},
+			wantErr: true,
+		},
+		{
+			name:       "MissingUp",
+			migrations: []migrate.Migration{{Version: "1", Name: "first"}},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				_, err := migrate.New(nil, migrate.Config{Migrations: tt.migrations})
+				if (err != nil) != tt.wantErr {
+					t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+	migrate.Register(migrate.Migration{Version: "900010", Name: "tenth", Up: noop})
+	migrate.Register(migrate.Migration{Version: "900002", Name: "second", Up: noop})
+
+	var versions []string
+	for _, m := range migrate.Registered() {
+		if m.Version == "900010" || m.Version == "900002" {
+			versions = append(versions, m.Version)
+		}
+	}
+	if !slices.Equal(versions, []string{"900002", "900010"}) {
+		t.Errorf("Expected numeric version order, got %v", versions)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Register to panic on a duplicate version")
+		}
+	}()
+	migrate.Register(migrate.Migration{Version: "900002", Name: "duplicate", Up: noop})
+}
+
+func TestBatchSize(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		dialect   driver.Dialect
+		batchSize int
+		want      int
+	}{
+		{name: "DialectMaximum", dialect: driver.CosmosDBDialect{}, want: 25},
+		{name: "BelowMaximum", dialect: driver.CosmosDBDialect{}, batchSize: 10, want: 10},
+		{name: "CappedAtMaximum", dialect: driver.NeptuneDialect{}, batchSize: 500, want: 100},
+		{name: "Unlimited", dialect: driver.TinkerGraphDialect{}, batchSize: 5000, want: 5000},
+		{name: "UnlimitedDefault", dialect: driver.TinkerGraphDialect{}, want: 1000},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				t.Parallel()
+				if got := migrate.BatchSizeForTest(tt.dialect, tt.batchSize); got != tt.want {
+					t.Errorf("Expected batch size %d, got %d", tt.want, got)
+				}
+			},
+		)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	db := openDB(t)
+	var ran []string
+	step := func(name string) func(*driver.GremlinDriver) error {
+		return func(*driver.GremlinDriver) error {
+			ran = append(ran, name)
+			return nil
+		}
+	}
+	first, second := uniqueVersion(0), uniqueVersion(1)
+	migrations := []migrate.Migration{
+		{Version: second, Name: "second", Up: step("up second"), Down: step("down second")},
+		{Version: first, Name: "first", Up: step("up first")},
+	}
+	migrator, err := migrate.New(db, migrate.Config{Migrations: migrations})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = driver.Model[any](db).Labels(migrate.MigrationLabel).
+			Where("version", comparator.IN, []string{first, second}).
+			Delete()
+	})
+
+	if err = migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if err = migrator.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(ran, []string{"up first", "up second"}) {
+		t.Errorf("Expected each migration to run once in order, got %v", ran)
+	}
+	statuses, err := migrator.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, status := range statuses {
+		if (status.Version == first || status.Version == second) && (!status.Applied || status.Modified) {
+			t.Errorf("Expected %s to be applied and unmodified, got %+v", status.Version, status)
+		}
+	}
+
+	if err = migrator.Down(); err != nil {
+		t.Fatal(err)
+	}
+	if ran[len(ran)-1] != "down second" {
+		t.Errorf("Expected the last migration to be reverted, got %v", ran)
+	}
+	if err = migrator.Down(); !errors.Is(err, migrate.ErrNoDown) {
+		t.Errorf("Expected ErrNoDown, got %v", err)
+	}
+
+	changes := []struct {
+		name      string
+		migration migrate.Migration
+	}{
+		{name: "Renamed", migration: migrate.Migration{Version: first, Name: "renamed", Up: noop}},
+		{name: "Edited", migration: migrate.Migration{Version: first, Name: "first", Checksum: "v2", Up: noop}},
+	}
+	for _, change := range changes {
+		changed, err := migrate.New(db, migrate.Config{Migrations: []migrate.Migration{change.migration}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = changed.Up(); !errors.Is(err, migrate.ErrChecksumMismatch) {
+			t.Errorf("%s: expected ErrChecksumMismatch, got %v", change.name, err)
+		}
+		statuses, err = changed.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, status := range statuses {
+			if status.Version == first && !status.Modified {
+				t.Errorf("%s: expected %s to be modified, got %+v", change.name, first, status)
+			}
+		}
+	}
+}
+
+func TestMigrationLock(t *testing.T) {
+	db := openDB(t)
+	holder, err := migrate.New(db, migrate.Config{Migrations: []migrate.Migration{}, Owner: "a-holder"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = holder.AcquireLockForTest(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = holder.ReleaseLockForTest() })
+
+	other, err := migrate.New(db, migrate.Config{Migrations: []migrate.Migration{}, Owner: "b-other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = other.Up(); !errors.Is(err, migrate.ErrLocked) {
+		t.Errorf("Expected ErrLocked while another runner holds the lock, got %v", err)
+	}
+	if err = holder.ReleaseLockForTest(); err != nil {
+		t.Fatal(err)
+	}
+	if err = other.Up(); err != nil {
+		t.Errorf("Expected Up to run once the lock is released, got %v", err)
+	}
+
+	// Lock vertices created by two runners at the same moment lock out both.
+	for _, owner := range []string{"a-holder", "z-rival"} {
+		err = db.Iterate(
+			db.G().AddV(migrate.LockLabel).
+				Property("owner", owner).
+				Property("expires_at", time.Now().UTC().Add(time.Hour)),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() { _ = db.Iterate(db.G().V().HasLabel(migrate.LockLabel).Has("owner", "z-rival").Drop()) })
+	if err = holder.AcquireLockForTest(); !errors.Is(err, migrate.ErrLocked) {
+		t.Errorf("Expected ErrLocked beside another lock vertex, got %v", err)
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	db := openDB(t)
+	label := fmt.Sprintf("test_migrated_user_%d", time.Now().UnixNano())
+	t.Cleanup(func() {
+		_ = db.Iterate(db.G().V().HasLabel(label, label+"_v2").Drop())
+	})
+	ids := make([]any, 0, 3)
+	for i := range 3 {
+		results, err := db.ToList(
+			db.G().AddV(label).
+				Property("fullname", fmt.Sprintf("user %d", i)).
+				Property("mail", fmt.Sprintf("user%d@example.com", i)).
+				Id(),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, results[0].GetInterface())
+	}
+	if err := db.Iterate(db.G().V(ids[0]).AddE("follows").To(db.G().V(ids[1])).Property("since", 2020)); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := migrate.RenameProperty(db, label, "fullname", "fullname", 2); err != nil || n != 0 {
+		t.Fatalf("RenameProperty() to the same name = %d, %v; want 0", n, err)
+	}
+	if n, err := migrate.RenameProperty(db, label, "fullname", "name", 2); err != nil || n != 3 {
+		t.Fatalf("RenameProperty() = %d, %v; want 3", n, err)
+	}
+	if n, err := migrate.CopyProperty(db, label, "mail", "email", 2); err != nil || n != 3 {
+		t.Fatalf("CopyProperty() = %d, %v; want 3", n, err)
+	}
+	if n, err := migrate.RelabelVertices(db, label, label+"_v2", 2); err != nil || n != 3 {
+		t.Fatalf("RelabelVertices() = %d, %v; want 3", n, err)
+	}
+
+	users, err := driver.Model[testMigratedUser](db).Labels(label + "_v2").Find()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("Expected 3 relabeled users, got %d", len(users))
+	}
+	for _, user := range users {
+		if user.Name == "" || user.Email == "" {
+			t.Errorf("Expected renamed and copied properties, got %+v", user)
+		}
+	}
+	edges, err := db.ToList(db.G().V().HasLabel(label + "_v2").OutE("follows").Values("since"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 1 {
+		t.Errorf("Expected the follows edge to move with its vertices, got %d", len(edges))
+	}
+}
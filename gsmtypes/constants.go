@@ -4,4 +4,5 @@ const (
 	GremlinTag             = "gremlin"
 	GremlinSubTraversalTag = "gremlinSubTraversal"
 	GremlinEdgeTag         = "gremlinEdge"
+	ValidateTag            = "gsmvalidate"
 )
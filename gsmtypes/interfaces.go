@@ -11,10 +11,22 @@ type VertexType interface {
 	SetVertexCreatedAt(t time.Time)
 }
 
+type SoftDeleteType interface {
+	GetDeletedAt() time.Time
+	IsDeleted() bool
+}
+
+// NoTimestampsType is implemented by models whose created and last modified
+// timestamps the driver does not maintain, typically by embedding
+// NoTimestamps.
+type NoTimestampsType interface {
+	TimestampsDisabled()
+}
+
 type EdgeType interface {
 	GetEdgeID() any
-	GetEdgeLastModified() string
-	GetEdgeCreatedAt() int64
+	GetEdgeLastModified() time.Time
+	GetEdgeCreatedAt() time.Time
 }
 
 type CustomLabelType interface {
@@ -5,6 +5,7 @@ import "time"
 const (
 	LastModified = "last_modified"
 	CreatedAt    = "created_at"
+	DeletedAt    = "deleted_at"
 )
 
 type Vertex struct {
@@ -25,15 +26,31 @@ func (v *Vertex) SetVertexID(id any)                { v.ID = id }
 func (v *Vertex) SetVertexLastModified(t time.Time) { v.LastModified = t }
 func (v *Vertex) SetVertexCreatedAt(t time.Time)    { v.CreatedAt = t }
 
+// SoftDelete makes Query.Delete set deleted_at instead of dropping the
+// vertex, and queries skip vertices with deleted_at set. Embed it next to
+// Vertex.
+type SoftDelete struct {
+	DeletedAt time.Time `json:"deleted_at" gremlin:"deleted_at,omitempty"`
+}
+
+func (s *SoftDelete) GetDeletedAt() time.Time { return s.DeletedAt }
+func (s *SoftDelete) IsDeleted() bool         { return !s.DeletedAt.IsZero() }
+
+// NoTimestamps stops the driver from setting created_at and last_modified
+// on a model. Embed it next to Vertex.
+type NoTimestamps struct{}
+
+func (NoTimestamps) TimestampsDisabled() {}
+
 type Edge struct {
-	ID           any    `json:"id"            gremlin:"id"`
-	LastModified string `json:"last_modified" gremlin:"last_modified"`
-	CreatedAt    int64  `json:"created_at"    gremlin:"created_at"`
+	ID           any       `json:"id"            gremlin:"id"`
+	LastModified time.Time `json:"last_modified" gremlin:"last_modified"`
+	CreatedAt    time.Time `json:"created_at"    gremlin:"created_at"`
 }
 
-func (e Edge) GetEdgeID() any              { return e.ID }
-func (e Edge) GetEdgeLastModified() string { return e.LastModified }
-func (e Edge) GetEdgeCreatedAt() int64     { return e.CreatedAt }
+func (e Edge) GetEdgeID() any                 { return e.ID }
+func (e Edge) GetEdgeLastModified() time.Time { return e.LastModified }
+func (e Edge) GetEdgeCreatedAt() time.Time    { return e.CreatedAt }
 func (e Edge) Label() string {
 	// Default implementation returns empty string
 	// The driver will use struct name normalization when Label() returns empty